@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/egonelbre/async"
+)
+
+// parallelFor calls fn(worker, i) for every i in [0, n) from `procs` goroutines, numbered from 0
+// as `worker`, which take indices from a shared counter so that expensive indices do not hold up
+// the others.
+func parallelFor(n uint32, procs int, fn func(worker int, i uint32)) {
+	if procs <= 1 {
+		for i := u0; i < n; i++ {
+			fn(0, i)
+		}
+		return
+	}
+	next := uint32(0)
+	wait := make(chan struct{})
+	async.Spawn(procs, func(worker int) {
+		runtime.LockOSThread()
+		for {
+			i := atomic.AddUint32(&next, 1) - 1
+			if i >= n {
+				return
+			}
+			fn(worker, i)
+		}
+	}, func() { wait <- struct{}{} })
+	<-wait
+}
+
+// AllPairsBFS computes a vector of levels from every vertex with BFS, using `procs` goroutines,
+// and calls fn with each source and its vector of levels, which fn may keep. The rows are
+// streamed rather than collected, so the memory used is proportional to NumVertices * procs.
+// fn is called concurrently from different goroutines when `procs` is greater than 1.
+func AllPairsBFS(g Graph, procs int, fn func(src uint32, vertLevel []uint32)) {
+	parallelFor(g.NumVertices(), procs, func(_ int, src uint32) {
+		_, vertLevel := BFS(g, src)
+		fn(src, vertLevel)
+	})
+}
+
+// AllPairsDijkstra runs Dijkstra from every vertex with `procs` goroutines and calls fn, concurrently
+// if `procs` is greater than 1, with each source and its DijkstraState, which fn may keep.
+func AllPairsDijkstra(g Graph, weightFn func(uint32, uint32) float32, withPreds bool, procs int, fn func(src uint32, ds DijkstraState)) {
+	parallelFor(g.NumVertices(), procs, func(_ int, src uint32) {
+		fn(src, Dijkstra(g, src, weightFn, withPreds))
+	})
+}
+
+// Johnson computes shortest paths as AllPairsDijkstra does, allowing negative weights by reweighting
+// the edges with Bellman-Ford potentials. It returns a NegativeCycleError, without calling fn, if g has one.
+func Johnson(g Graph, weightFn func(uint32, uint32) float32, withPreds bool, procs int, fn func(src uint32, ds DijkstraState)) error {
+	w := newWeigher(g, weightFn)
+	// distances from a virtual vertex with an edge of weight 0 to every vertex.
+	potentials := make([]float32, g.NumVertices())
+	if err := bellmanFordRounds(g, w, potentials); err != nil {
+		return err
+	}
+
+	reweighted := weightFn
+	for _, p := range potentials {
+		if p != 0 {
+			reweighted = func(u, v uint32) float32 {
+				wt := w.lookup(u, v) + potentials[u] - potentials[v]
+				if wt < 0 { // rounding error
+					return 0
+				}
+				return wt
+			}
+			break
+		}
+	}
+	parallelFor(g.NumVertices(), procs, func(_ int, src uint32) {
+		ds := Dijkstra(g, src, reweighted, withPreds)
+		for v, d := range ds.Dists {
+			if d < maxDist {
+				ds.Dists[v] = d - potentials[src] + potentials[v]
+			}
+		}
+		fn(src, ds)
+	})
+	return nil
+}
+
+// FloydWarshall returns matrices of the shortest path distances and parents between every pair of
+// vertices, allowing negative weights, or a NegativeCycleError. Unreachable pairs have a distance of
+// math.MaxFloat32 and a parent of NoParent, and parents[u][u] is NoParent. It suits small, dense graphs.
+func FloydWarshall(g Graph, weightFn func(uint32, uint32) float32) (dists [][]float32, parents [][]uint32, err error) {
+	w := newWeigher(g, weightFn)
+	nv := g.NumVertices()
+	dists = make([][]float32, nv)
+	parents = make([][]uint32, nv)
+	for u := u0; u < nv; u++ {
+		dists[u] = make([]float32, nv)
+		parents[u] = make([]uint32, nv)
+		for v := range dists[u] {
+			dists[u][v] = maxDist
+			parents[u][v] = NoParent
+		}
+		dists[u][u] = 0
+		vs, ws := w.outEdges(g, u)
+		for i, v := range vs {
+			if wt := w.weight(u, v, ws, i); wt < dists[u][v] {
+				dists[u][v] = wt
+				parents[u][v] = u
+			}
+		}
+	}
+
+	for k := u0; k < nv; k++ {
+		dk, pk := dists[k], parents[k]
+		for u := u0; u < nv; u++ {
+			du, pu := dists[u], parents[u]
+			if du[k] >= maxDist {
+				continue
+			}
+			for v := u0; v < nv; v++ {
+				if dk[v] >= maxDist {
+					continue
+				}
+				if alt := du[k] + dk[v]; alt < du[v] {
+					du[v] = alt
+					pu[v] = pk[v]
+				}
+			}
+		}
+	}
+
+	for u := u0; u < nv; u++ {
+		if dists[u][u] < 0 {
+			// later updates may have rewritten parents[u] after dists[u][u] turned negative, so find
+			// the cycle with Bellman-Ford from u, which reaches it.
+			if _, err := bellmanFordDists(g, w, u); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return dists, parents, nil
+}
@@ -0,0 +1,170 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// cycleWeight returns the weight of the cycle through the vertices of cycle in order, and false
+// if g lacks one of its edges.
+func cycleWeight(g WeightedGraph, cycle []uint32) (float32, bool) {
+	total := float32(0)
+	for i, u := range cycle {
+		wt, ok := g.Weight(u, cycle[(i+1)%len(cycle)])
+		if !ok {
+			return 0, false
+		}
+		total += wt
+	}
+	return total, true
+}
+
+// checkNegativeCycle fails unless err is a NegativeCycleError holding a cycle of g of negative weight.
+func checkNegativeCycle(t *testing.T, name string, g WeightedGraph, err error) {
+	t.Helper()
+	var nce NegativeCycleError
+	if !errors.As(err, &nce) {
+		t.Fatalf("%s %v: error = %v, expected a NegativeCycleError", name, g, err)
+	}
+	if wt, ok := cycleWeight(g, nce.Cycle); !ok || wt >= 0 {
+		t.Fatalf("%s %v: cycle %v has weight %v and edges %v, expected a negative cycle", name, g, nce.Cycle, wt, ok)
+	}
+}
+
+func TestFloydWarshallNegativeCycle(t *testing.T) {
+	g, err := NewWeightedDiGraph(
+		[]uint32{2, 2, 0, 4, 3, 1, 5, 1, 5},
+		[]uint32{0, 2, 1, 2, 0, 5, 4, 3, 5},
+		[]float32{0, -2, 0, 3, -1, -1, 3, 1, 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = FloydWarshall(g, nil)
+	checkNegativeCycle(t, "FloydWarshall", g, err)
+
+	r := rand.New(rand.NewSource(15))
+	for i := 0; i < 200; i++ {
+		nv := 1 + r.Intn(10)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(3*nv))
+		for j := range ws {
+			ws[j] -= 2
+		}
+		g, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := FloydWarshall(g, nil); err != nil {
+			checkNegativeCycle(t, "FloydWarshall", g, err)
+		}
+	}
+}
+
+func TestFloydWarshall(t *testing.T) {
+	// the negative edge 2 -> 1 makes 0 -> 2 -> 1 shorter than 0 -> 1.
+	g, err := NewWeightedDiGraph([]uint32{0, 0, 2, 1}, []uint32{1, 2, 1, 3}, []float32{4, 5, -3, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := float32(math.MaxFloat32)
+	wantDists := [][]float32{{0, 2, 5, 4}, {m, 0, m, 2}, {m, -3, 0, -1}, {m, m, m, 0}}
+	wantParents := [][]uint32{
+		{NoParent, 2, 0, 1},
+		{NoParent, NoParent, NoParent, 1},
+		{NoParent, 2, NoParent, 1},
+		{NoParent, NoParent, NoParent, NoParent},
+	}
+	dists, parents, err := FloydWarshall(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dists, wantDists) || !reflect.DeepEqual(parents, wantParents) {
+		t.Errorf("FloydWarshall = %v, %v, expected %v, %v", dists, parents, wantDists, wantParents)
+	}
+
+	var mu sync.Mutex
+	johnson := make([][]float32, g.NumVertices())
+	err = Johnson(g, nil, false, 2, func(src uint32, ds DijkstraState) {
+		mu.Lock()
+		johnson[src] = ds.Dists
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(johnson, wantDists) {
+		t.Errorf("Johnson distances = %v, expected %v", johnson, wantDists)
+	}
+}
+
+func TestJohnsonMatchesFloydWarshall(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	for i := 0; i < 200; i++ {
+		nv := 1 + r.Intn(15)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(3*nv))
+		for j := range ws {
+			ws[j]--
+		}
+		g, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dists, _, fwErr := FloydWarshall(g, nil)
+		forProcs(func(procs int) {
+			var mu sync.Mutex
+			states := make([]DijkstraState, nv)
+			err := Johnson(g, nil, true, procs, func(src uint32, ds DijkstraState) {
+				mu.Lock()
+				states[src] = ds
+				mu.Unlock()
+			})
+			if fwErr != nil {
+				checkNegativeCycle(t, "Johnson", g, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v: Johnson returned %v, but FloydWarshall found no negative cycle", g, err)
+			}
+			for src, ds := range states {
+				if !reflect.DeepEqual(ds.Dists, dists[src]) {
+					t.Fatalf("%v: Johnson distances from %d = %v, but FloydWarshall = %v", g, src, ds.Dists, dists[src])
+				}
+				want, err := BellmanFord(g, uint32(src), nil, true)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkSame(t, g, "Johnson", procs, ds, want)
+			}
+		})
+	}
+}
+
+func TestAllPairsMatchesSingleSource(t *testing.T) {
+	for _, g := range randomGraphs(t, 15, 20, 40) {
+		nv := g.NumVertices()
+		forProcs(func(procs int) {
+			var mu sync.Mutex
+			levels := make([][]uint32, nv)
+			AllPairsBFS(g, procs, func(src uint32, vertLevel []uint32) {
+				mu.Lock()
+				levels[src] = vertLevel
+				mu.Unlock()
+			})
+			states := make([]DijkstraState, nv)
+			AllPairsDijkstra(g, nil, true, procs, func(src uint32, ds DijkstraState) {
+				mu.Lock()
+				states[src] = ds
+				mu.Unlock()
+			})
+			for src := u0; src < nv; src++ {
+				_, want := BFS(g, src)
+				checkSame(t, g, "AllPairsBFS", procs, levels[src], want)
+				checkSame(t, g, "AllPairsDijkstra", procs, states[src], Dijkstra(g, src, nil, true))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package graph
+
+// AStar performs an A* search and returns a path from `src` to `dst`, its cost and true, or false if
+// `dst` is unreachable. The path is a shortest path if `heuristic` never overestimates the cost to
+// `dst`; a nil heuristic makes the search Dijkstra's. Weights must be non-negative.
+func AStar(g Graph, src, dst uint32, weightFn func(uint32, uint32) float32, heuristic func(uint32) float32) ([]uint32, float32, bool) {
+	return aStar(g, src, dst, newWeigher(g, weightFn), heuristic, nil)
+}
+
+// aStar performs the search of AStar with weights from w, skipping the edges from u to v for which
+// blocked(u, v) is true if blocked is not nil.
+func aStar(g Graph, src, dst uint32, w weigher, heuristic func(uint32) float32, blocked func(u, v uint32) bool) ([]uint32, float32, bool) {
+	if heuristic == nil {
+		heuristic = func(uint32) float32 { return 0 }
+	}
+	costs := map[uint32]float32{src: 0}
+	parents := map[uint32]uint32{src: src}
+	h := distHeap{}
+	h.Push(src, heuristic(src))
+
+	for h.Len() > 0 {
+		u, f := h.Pop()
+		cost := costs[u]
+		if f > min(maxDist, cost+heuristic(u)) { // stale entry
+			continue
+		}
+		if u == dst {
+			path := []uint32{}
+			for v := dst; v != src; v = parents[v] {
+				path = append(path, v)
+			}
+			path = append(path, src)
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, cost, true
+		}
+		vs, ws := w.outEdges(g, u)
+		for i, v := range vs {
+			if blocked != nil && blocked(u, v) {
+				continue
+			}
+			alt := min(maxDist, cost+w.weight(u, v, ws, i))
+			if old, seen := costs[v]; (seen && alt >= old) || alt >= maxDist {
+				continue
+			}
+			costs[v] = alt
+			parents[v] = u
+			h.Push(v, min(maxDist, alt+heuristic(v)))
+		}
+	}
+	return nil, 0, false
+}
+
+// Landmarks holds the shortest path distances between a set of landmark vertices and every
+// vertex of a graph, from which it derives ALT (A*, landmarks and the triangle inequality)
+// heuristics for AStar.
+type Landmarks struct {
+	landmarks []uint32
+	from      [][]float32 // from[i][v] is the distance from landmark i to v
+	to        [][]float32 // to[i][v] is the distance from v to landmark i
+}
+
+// NewLandmarks computes the distances from and to each of the vertices in `landmarks` with
+// Dijkstra. Weights are given by `weightFn` as for Dijkstra, and must be the same as those used
+// by AStar. Landmarks far apart on the edges of the graph usually give the best estimates.
+// It stores two vectors of NumVertices distances per landmark, or one for undirected graphs.
+func NewLandmarks(g Graph, landmarks []uint32, weightFn func(uint32, uint32) float32) Landmarks {
+	l := Landmarks{
+		landmarks: landmarks,
+		from:      make([][]float32, len(landmarks)),
+		to:        make([][]float32, len(landmarks)),
+	}
+	rg, rfn := reverse(g), reverseWeightFn(weightFn)
+	for i, lm := range landmarks {
+		l.from[i] = Dijkstra(g, lm, weightFn, false).Dists
+		if g.IsDirected() {
+			l.to[i] = Dijkstra(rg, lm, rfn, false).Dists
+		} else {
+			l.to[i] = l.from[i]
+		}
+	}
+	return l
+}
+
+// Landmarks returns the landmark vertices.
+func (l Landmarks) Landmarks() []uint32 {
+	return l.landmarks
+}
+
+// Heuristic returns an admissible heuristic for AStar searches to `dst`. By the triangle
+// inequality, the distance from v to dst is at least dist(L, dst) - dist(L, v) and
+// dist(v, L) - dist(dst, L) for each landmark L; the heuristic is the largest of these bounds.
+func (l Landmarks) Heuristic(dst uint32) func(uint32) float32 {
+	return func(v uint32) float32 {
+		est := float32(0)
+		for i := range l.landmarks {
+			from, to := l.from[i], l.to[i]
+			if from[dst] < maxDist && from[v] < maxDist && from[dst]-from[v] > est {
+				est = from[dst] - from[v]
+			}
+			if to[v] < maxDist && to[dst] < maxDist && to[v]-to[dst] > est {
+				est = to[v] - to[dst]
+			}
+		}
+		return est
+	}
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAStar(t *testing.T) {
+	// 0 -> 2 -> 3 -> 4 costs 4; 0 -> 1 -> 3 -> 4 costs 7. Vertex 5 is unreachable.
+	g, err := NewWeightedDiGraph([]uint32{0, 0, 1, 2, 3, 5}, []uint32{1, 2, 3, 3, 4, 5}, []float32{1, 2, 5, 1, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLandmarks(g, []uint32{0, 4}, nil)
+	for name, h := range map[string]func(uint32) float32{"nil": nil, "landmarks": l.Heuristic(4)} {
+		path, cost, ok := AStar(g, 0, 4, nil, h)
+		if !ok || cost != 4 || !reflect.DeepEqual(path, []uint32{0, 2, 3, 4}) {
+			t.Errorf("%s heuristic: AStar(0, 4) = %v, %v, %v, expected [0 2 3 4], 4, true", name, path, cost, ok)
+		}
+	}
+	if path, _, ok := AStar(g, 0, 5, nil, nil); ok || path != nil {
+		t.Errorf("AStar(0, 5) = %v, %v, expected nil, false", path, ok)
+	}
+	if path, cost, ok := AStar(g, 3, 3, nil, nil); !ok || cost != 0 || !reflect.DeepEqual(path, []uint32{3}) {
+		t.Errorf("AStar(3, 3) = %v, %v, %v, expected [3], 0, true", path, cost, ok)
+	}
+}
+
+func TestAStarMatchesDijkstra(t *testing.T) {
+	for _, g := range randomGraphs(t, 14, 40, 25) {
+		nv := g.NumVertices()
+		w := newWeigher(g, nil)
+		l := NewLandmarks(g, []uint32{0, nv / 2, nv - 1}, nil)
+		states := make([]DijkstraState, nv)
+		for v := range states {
+			states[v] = Dijkstra(g, uint32(v), nil, false)
+		}
+		for dst := u0; dst < nv; dst++ {
+			heuristic := l.Heuristic(dst)
+			for v, s := range states {
+				if s.HasPathTo(dst) && heuristic(uint32(v)) > s.Dists[dst] {
+					t.Fatalf("%v: landmark heuristic for %d to %d is %v, above the distance %v", g, v, dst, heuristic(uint32(v)), s.Dists[dst])
+				}
+			}
+			for src, s := range states {
+				src := uint32(src)
+				for name, h := range map[string]func(uint32) float32{"nil": nil, "landmarks": heuristic} {
+					path, cost, ok := AStar(g, src, dst, nil, h)
+					if ok != s.HasPathTo(dst) {
+						t.Fatalf("%v: %s heuristic: AStar(%d, %d) found a path: %v", g, name, src, dst, ok)
+					}
+					if !ok {
+						continue
+					}
+					if cost != s.Dists[dst] || pathCost(w, path) != cost || path[0] != src || path[len(path)-1] != dst {
+						t.Fatalf("%v: %s heuristic: AStar(%d, %d) = %v, %v, expected a path of cost %v", g, name, src, dst, path, cost, s.Dists[dst])
+					}
+				}
+			}
+		}
+	}
+}
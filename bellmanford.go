@@ -0,0 +1,158 @@
+package graph
+
+import "fmt"
+
+// NegativeCycleError is returned by shortest path algorithms that accept negative weights when
+// a cycle of negative total weight is reachable from the source, so that no shortest paths exist.
+// Note that in an undirected graph, any edge with a negative weight is a negative cycle.
+type NegativeCycleError struct {
+	// Cycle holds the vertices of one negative cycle in order; the last vertex has an edge to the first.
+	Cycle []uint32
+}
+
+func (e NegativeCycleError) Error() string {
+	return fmt.Sprintf("negative cycle: %v", e.Cycle)
+}
+
+// relax lowers the distance of each out neighbor v of u for which dists[u] + weight(u, v) is
+// shorter, recording u in via[v] and calling fn(v) for each one.
+func relax(g Graph, w weigher, u uint32, dists []float32, via []uint32, fn func(v uint32)) {
+	if dists[u] >= maxDist {
+		return
+	}
+	vs, ws := w.outEdges(g, u)
+	for i, v := range vs {
+		alt := min(maxDist, dists[u]+w.weight(u, v, ws, i))
+		if alt < dists[v] {
+			dists[v] = alt
+			via[v] = u
+			fn(v)
+		}
+	}
+}
+
+// bellmanFordDists computes the shortest path distances from src with bellmanFordRounds.
+func bellmanFordDists(g Graph, w weigher, src uint32) ([]float32, error) {
+	dists := make([]float32, g.NumVertices())
+	for i := range dists {
+		dists[i] = maxDist
+	}
+	dists[src] = 0
+	if err := bellmanFordRounds(g, w, dists); err != nil {
+		return nil, err
+	}
+	return dists, nil
+}
+
+// bellmanFordRounds lowers the distances in dists with rounds of relaxation over all edges,
+// stopping when a round lowers no distances. Without negative cycles reachable from the vertices
+// with finite distances, this happens by round nv; otherwise it returns a NegativeCycleError.
+func bellmanFordRounds(g Graph, w weigher, dists []float32) error {
+	nv := g.NumVertices()
+	via := make([]uint32, nv)
+	for i := range via {
+		via[i] = NoParent
+	}
+
+	// a vertex whose distance is lowered in round nv has a via chain of at least nv edges, which
+	// contains a negative cycle.
+	var lowered uint32
+	for round := u0; round < nv; round++ {
+		lowered = NoParent
+		for u := u0; u < nv; u++ {
+			relax(g, w, u, dists, via, func(v uint32) { lowered = v })
+		}
+		if lowered == NoParent {
+			return nil
+		}
+	}
+	return NegativeCycleError{Cycle: viaCycle(via, lowered, nv)}
+}
+
+// viaCycle returns the cycle reached by following via from v, which must lead to a cycle
+// within nv steps, in the direction of the edges.
+func viaCycle(via []uint32, v, nv uint32) []uint32 {
+	for i := u0; i < nv; i++ {
+		v = via[v]
+	}
+	cycle := []uint32{v}
+	for u := via[v]; u != v; u = via[u] {
+		cycle = append(cycle, u)
+	}
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
+
+// BellmanFord performs a Bellman-Ford Shortest Paths calculation from vertex `src` and returns a
+// DijkstraState as Dijkstra does, allowing negative weights, or a NegativeCycleError if a cycle of
+// negative weight is reachable from `src`.
+func BellmanFord(g Graph, src uint32, weightFn func(uint32, uint32) float32, withPreds bool) (DijkstraState, error) {
+	w := newWeigher(g, weightFn)
+	dists, err := bellmanFordDists(g, w, src)
+	if err != nil {
+		return DijkstraState{}, err
+	}
+	parents, pathcounts, preds := shortestPathTree(g, w, src, dists, withPreds, 1)
+	ds := DijkstraState{
+		Parents:      parents,
+		Dists:        dists,
+		Pathcounts:   pathcounts,
+		Predecessors: preds,
+	}
+	return ds, nil
+}
+
+// SPFA returns the same DijkstraState and errors as BellmanFord, but only relaxes the out edges of
+// vertices whose distance changed, which is usually much faster.
+func SPFA(g Graph, src uint32, weightFn func(uint32, uint32) float32, withPreds bool) (DijkstraState, error) {
+	w := newWeigher(g, weightFn)
+	nv := g.NumVertices()
+	dists := make([]float32, nv)
+	via := make([]uint32, nv)
+	hops := make([]uint32, nv) // the number of edges in the path found to each vertex
+	queued := make([]bool, nv)
+	for i := range dists {
+		dists[i] = maxDist
+		via[i] = NoParent
+	}
+	dists[src] = 0
+
+	queue := []uint32{src}
+	queued[src] = true
+	cycle := false
+	for len(queue) > 0 && !cycle {
+		u := queue[0]
+		queue = queue[1:]
+		queued[u] = false
+		relax(g, w, u, dists, via, func(v uint32) {
+			hops[v] = hops[u] + 1
+			if hops[v] >= nv {
+				cycle = true
+			}
+			if !queued[v] {
+				queued[v] = true
+				queue = append(queue, v)
+			}
+		})
+	}
+	if cycle {
+		// a path with nv edges repeats a vertex, so a negative cycle exists. Bellman-Ford finds
+		// one from a consistent set of via links.
+		d, err := bellmanFordDists(g, w, src)
+		if err != nil {
+			return DijkstraState{}, err
+		}
+		dists = d
+	}
+
+	parents, pathcounts, preds := shortestPathTree(g, w, src, dists, withPreds, 1)
+	ds := DijkstraState{
+		Parents:      parents,
+		Dists:        dists,
+		Pathcounts:   pathcounts,
+		Predecessors: preds,
+	}
+	return ds, nil
+}
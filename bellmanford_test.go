@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBellmanFord(t *testing.T) {
+	// the negative edge 2 -> 1 makes 0 -> 2 -> 1 shorter than 0 -> 1.
+	g, err := NewWeightedDiGraph([]uint32{0, 0, 2, 1}, []uint32{1, 2, 1, 3}, []float32{4, 5, -3, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DijkstraState{
+		Parents:      []uint32{NoParent, 2, 0, 1},
+		Dists:        []float32{0, 2, 5, 4},
+		Pathcounts:   []uint32{1, 1, 1, 1},
+		Predecessors: [][]uint32{nil, {2}, {0}, {1}},
+	}
+	for name, fn := range map[string]func(Graph, uint32, func(uint32, uint32) float32, bool) (DijkstraState, error){
+		"BellmanFord": BellmanFord, "SPFA": SPFA,
+	} {
+		s, err := fn(g, 0, nil, true)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(s, want) {
+			t.Errorf("%s = %v, expected %v", name, s, want)
+		}
+	}
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	// 1 -> 2 -> 1 weighs -1 and is reachable from 0 but not from 3.
+	g, err := NewWeightedDiGraph([]uint32{0, 1, 2, 3}, []uint32{1, 2, 1, 0}, []float32{1, -2, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = BellmanFord(g, 0, nil, false)
+	checkNegativeCycle(t, "BellmanFord", g, err)
+	_, err = SPFA(g, 0, nil, false)
+	checkNegativeCycle(t, "SPFA", g, err)
+
+	g, err = NewWeightedDiGraph([]uint32{0, 2, 3}, []uint32{1, 3, 2}, []float32{1, -1, -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BellmanFord(g, 0, nil, false); err != nil {
+		t.Errorf("BellmanFord returned %v for an unreachable negative cycle", err)
+	}
+
+	// an undirected edge of negative weight is a negative cycle.
+	ug, err := NewWeighted([]uint32{0, 1}, []uint32{1, 2}, []float32{1, -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SPFA(ug, 0, nil, false)
+	checkNegativeCycle(t, "SPFA", ug, err)
+}
+
+func TestBellmanFordMatchesFloydWarshall(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 200; i++ {
+		nv := 1 + r.Intn(15)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(3*nv))
+		for j := range ws {
+			ws[j] -= 1
+		}
+		g, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dists, _, fwErr := FloydWarshall(g, nil)
+		for src := u0; src < g.NumVertices(); src++ {
+			bf, err := BellmanFord(g, src, nil, true)
+			spfa, spfaErr := SPFA(g, src, nil, true)
+			if (err == nil) != (spfaErr == nil) {
+				t.Fatalf("%v: BellmanFord(%d) returned %v but SPFA returned %v", g, src, err, spfaErr)
+			}
+			if err != nil {
+				checkNegativeCycle(t, "BellmanFord", g, err)
+				checkNegativeCycle(t, "SPFA", g, spfaErr)
+				continue
+			}
+			if !reflect.DeepEqual(bf, spfa) {
+				t.Fatalf("%v: BellmanFord(%d) = %v, but SPFA = %v", g, src, bf, spfa)
+			}
+			if fwErr == nil && !reflect.DeepEqual(bf.Dists, dists[src]) {
+				t.Fatalf("%v: BellmanFord(%d) distances = %v, but FloydWarshall = %v", g, src, bf.Dists, dists[src])
+			}
+		}
+	}
+}
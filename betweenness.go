@@ -0,0 +1,246 @@
+package graph
+
+import "math/rand"
+
+// BetweennessOptions configures BetweennessCentrality.
+type BetweennessOptions struct {
+	// Normalize divides the scores by the number of pairs of vertices that do not include the
+	// vertex, or of all pairs if Endpoints is set, so that they lie between 0 and 1.
+	Normalize bool
+	// Endpoints counts the source and destination of each shortest path as lying on it.
+	Endpoints bool
+	// WeightFn gives the weight of each edge. If it is nil, the weights stored in a WeightedGraph
+	// are used, or shortest paths are found with BFS for other graphs.
+	WeightFn func(uint32, uint32) float32
+	// Procs is the number of goroutines that compute shortest paths from different sources.
+	Procs int
+	// Samples, if positive and less than NumVertices, is the number of sources chosen at random
+	// from which shortest paths are computed. The scores are scaled up to estimate the exact
+	// scores, which takes time proportional to Samples rather than NumVertices.
+	Samples int
+	// Seed seeds the random choice of sources.
+	Seed int64
+}
+
+// sampleSources returns `samples` distinct vertices of an nv-vertex graph chosen at random with
+// `seed`, or every vertex if `samples` is not positive or not less than nv.
+func sampleSources(nv uint32, samples int, seed int64) []uint32 {
+	if samples <= 0 || samples >= int(nv) {
+		srcs := make([]uint32, nv)
+		for i := range srcs {
+			srcs[i] = uint32(i)
+		}
+		return srcs
+	}
+	r := rand.New(rand.NewSource(seed))
+	chosen := make(map[uint32]struct{}, samples)
+	srcs := make([]uint32, 0, samples)
+	for len(srcs) < samples {
+		v := uint32(r.Int63n(int64(nv)))
+		if _, ok := chosen[v]; !ok {
+			chosen[v] = struct{}{}
+			srcs = append(srcs, v)
+		}
+	}
+	return srcs
+}
+
+// brandesWorker holds the scores accumulated by one goroutine and its scratch vectors, which
+// are allocated once and reset after each source for the vertices that source reached.
+type brandesWorker struct {
+	scores       []float64
+	sigma, delta []float64
+	dists        []float32
+	preds        [][]uint32
+	order        []uint32 // vertices reached from the source, each after its predecessors
+	settled      []bool
+	npreds       []uint32
+	scratch      []uint32
+	reached      []uint32
+	h            distHeap
+}
+
+func newBrandesWorker(nv uint32) brandesWorker {
+	b := brandesWorker{
+		scores:  make([]float64, nv),
+		sigma:   make([]float64, nv),
+		delta:   make([]float64, nv),
+		dists:   make([]float32, nv),
+		preds:   make([][]uint32, nv),
+		settled: make([]bool, nv),
+		npreds:  make([]uint32, nv),
+	}
+	for i := range b.dists {
+		b.dists[i] = maxDist
+	}
+	return b
+}
+
+// bfs finds the shortest path predecessors and order of the vertices reachable from src in an
+// unweighted graph.
+func (b *brandesWorker) bfs(g Graph, src uint32) {
+	dists, preds := b.dists, b.preds
+	dists[src] = 0
+	b.order = append(b.order[:0], src)
+	for i := 0; i < len(b.order); i++ {
+		u := b.order[i]
+		alt := dists[u] + 1
+		for _, v := range g.OutNeighbors(u) {
+			if dists[v] == maxDist {
+				dists[v] = alt
+				b.order = append(b.order, v)
+			}
+			if dists[v] == alt {
+				preds[v] = append(preds[v], u)
+			}
+		}
+	}
+}
+
+// dijkstra finds the shortest path predecessors and order of the vertices reachable from src
+// with weights from w.
+func (b *brandesWorker) dijkstra(g Graph, w weigher, src uint32) {
+	dists, preds, settled := b.dists, b.preds, b.settled
+	dists[src] = 0
+	b.order = b.order[:0]
+	b.h.Push(src, 0)
+	reorder := false
+	for b.h.Len() > 0 {
+		u, d := b.h.Pop()
+		if d > dists[u] || settled[u] { // stale entry
+			continue
+		}
+		settled[u] = true
+		b.order = append(b.order, u)
+		vs, ws := w.outEdges(g, u)
+		for i, v := range vs {
+			if v == u || v == src {
+				continue
+			}
+			alt := min(maxDist, d+w.weight(u, v, ws, i))
+			switch {
+			case alt < dists[v]:
+				dists[v] = alt
+				preds[v] = append(preds[v][:0], u)
+				b.h.Push(v, alt)
+			case alt == dists[v] && alt < maxDist:
+				preds[v] = append(preds[v], u)
+				// a zero-weight edge to a vertex settled at the same distance.
+				reorder = reorder || settled[v]
+			}
+		}
+	}
+	if reorder {
+		b.sortOrder(g, w, src)
+	}
+}
+
+// sortOrder reorders b.order with releaseTight so that every vertex follows its predecessors,
+// dropping the predecessors around zero-weight cycles. It leaves b.settled set for b.order.
+func (b *brandesWorker) sortOrder(g Graph, w weigher, src uint32) {
+	for _, v := range b.order {
+		b.npreds[v] = uint32(len(b.preds[v]))
+		b.preds[v] = b.preds[v][:0]
+		b.settled[v] = false
+	}
+	sorted, reached := releaseTight(g, w, src, b.dists, b.npreds, b.settled, b.scratch, b.reached, func(u, v uint32) {
+		b.preds[v] = append(b.preds[v], u)
+	})
+	b.scratch, b.order, b.reached = b.order, sorted, reached
+}
+
+// accumulate adds the dependencies of src on every vertex, given the shortest path predecessors
+// and order found by bfs or dijkstra, then resets the scratch vectors. The number of shortest
+// paths to each vertex, sigma, is counted as a float64, since Pathcounts overflows on large graphs.
+func (b *brandesWorker) accumulate(src uint32, endpoints bool) {
+	sigma, delta, preds, order := b.sigma, b.delta, b.preds, b.order
+	for _, v := range order {
+		sigma[v] = 0
+		delta[v] = 0
+	}
+	sigma[src] = 1
+	for _, v := range order[1:] {
+		for _, u := range preds[v] {
+			sigma[v] += sigma[u]
+		}
+	}
+
+	if endpoints {
+		b.scores[src] += float64(len(order) - 1)
+	}
+	for i := len(order) - 1; i > 0; i-- {
+		v := order[i]
+		for _, u := range preds[v] {
+			delta[u] += sigma[u] / sigma[v] * (1 + delta[v])
+		}
+		b.scores[v] += delta[v]
+		if endpoints {
+			b.scores[v]++
+		}
+	}
+
+	for _, v := range order {
+		b.dists[v] = maxDist
+		preds[v] = preds[v][:0]
+		b.settled[v] = false
+	}
+}
+
+// BetweennessCentrality computes the betweenness centrality of every vertex, the sum over
+// pairs of other vertices s and t of the fraction of shortest paths from s to t that pass
+// through it, with Brandes' algorithm, and returns a vector of scores indexed by vertex.
+// In undirected graphs, each pair is counted once. Weights must be non-negative.
+// See BetweennessOptions for normalization, weights, parallelism and sampling.
+func BetweennessCentrality(g Graph, opts BetweennessOptions) []float64 {
+	nv := g.NumVertices()
+	w := newWeigher(g, opts.WeightFn)
+	unweighted := w.fn == nil && w.wg == nil
+	srcs := sampleSources(nv, opts.Samples, opts.Seed)
+	procs := opts.Procs
+	if procs < 1 {
+		procs = 1
+	}
+
+	workers := make([]brandesWorker, procs)
+	parallelFor(uint32(len(srcs)), procs, func(worker int, i uint32) {
+		b := &workers[worker]
+		if b.scores == nil {
+			*b = newBrandesWorker(nv)
+		}
+		if unweighted {
+			b.bfs(g, srcs[i])
+		} else {
+			b.dijkstra(g, w, srcs[i])
+		}
+		b.accumulate(srcs[i], opts.Endpoints)
+	})
+
+	scores := make([]float64, nv)
+	for _, b := range workers {
+		for v, s := range b.scores {
+			scores[v] += s
+		}
+	}
+
+	n := float64(nv)
+	scale := 1.0
+	switch {
+	case opts.Normalize && opts.Endpoints:
+		if nv > 1 {
+			scale = 1 / (n * (n - 1))
+		}
+	case opts.Normalize:
+		if nv > 2 {
+			scale = 1 / ((n - 1) * (n - 2))
+		}
+	case !g.IsDirected():
+		scale = 0.5
+	}
+	if len(srcs) < int(nv) {
+		scale *= n / float64(len(srcs))
+	}
+	for v := range scores {
+		scores[v] *= scale
+	}
+	return scores
+}
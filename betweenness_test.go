@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// pairBetweenness computes betweenness centrality from the Dijkstra distances and path counts
+// between every pair of vertices.
+func pairBetweenness(g Graph, weightFn func(uint32, uint32) float32, endpoints bool) []float64 {
+	nv := g.NumVertices()
+	st := make([]DijkstraState, nv)
+	for s := u0; s < nv; s++ {
+		st[s] = Dijkstra(g, s, weightFn, false)
+	}
+	bc := make([]float64, nv)
+	for s := u0; s < nv; s++ {
+		for t := u0; t < nv; t++ {
+			if s == t || !st[s].HasPathTo(t) {
+				continue
+			}
+			for v := u0; v < nv; v++ {
+				if v == s || v == t {
+					if endpoints {
+						bc[v]++
+					}
+					continue
+				}
+				if st[s].HasPathTo(v) && st[v].HasPathTo(t) && st[s].Dists[v]+st[v].Dists[t] == st[s].Dists[t] {
+					bc[v] += float64(st[s].Pathcounts[v]) * float64(st[v].Pathcounts[t]) / float64(st[s].Pathcounts[t])
+				}
+			}
+		}
+	}
+	if !g.IsDirected() {
+		for v := range bc {
+			bc[v] /= 2
+		}
+	}
+	return bc
+}
+
+// hasZeroWeightCycle returns true if g has a cycle of zero-weight edges other than self loops,
+// which includes any zero-weight edge of an undirected graph.
+func hasZeroWeightCycle(t *testing.T, g WeightedGraph) bool {
+	var ss, ds []uint32
+	for u := u0; u < g.NumVertices(); u++ {
+		vs, ws := g.OutEdgesWeighted(u)
+		for i, v := range vs {
+			if ws[i] == 0 && u != v {
+				ss, ds = append(ss, u), append(ds, v)
+			}
+		}
+	}
+	ss, ds = append(ss, g.NumVertices()-1), append(ds, g.NumVertices()-1)
+	zg, err := NewDiGraph(ss, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, sizes := StronglyConnectedComponents(zg)
+	return len(sizes) < int(g.NumVertices())
+}
+
+func TestBetweennessCentralityMatchesPairs(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	for _, wg := range randomGraphs(t, 17, 40, 30) {
+		var g Graph
+		nv := int(wg.NumVertices())
+		ss, ds, _ := randomEdges(r, nv, r.Intn(3*nv))
+		if wg.IsDirected() {
+			g, _ = NewDiGraph(ss, ds)
+		} else {
+			g, _ = New(ss, ds)
+		}
+		// around a zero-weight cycle, the paths counted from s to t through v are not the product
+		// of those from s to v and from v to t, so only the scores themselves are checked.
+		zeroCycle := hasZeroWeightCycle(t, wg)
+
+		for _, endpoints := range []bool{false, true} {
+			for procs := 1; procs <= 4; procs++ {
+				opts := BetweennessOptions{Endpoints: endpoints, Procs: procs}
+				scores := BetweennessCentrality(wg, opts)
+				for v, score := range scores {
+					if math.IsNaN(score) || score < 0 {
+						t.Fatalf("%v: score of %d is %v", wg, v, score)
+					}
+				}
+				if !zeroCycle {
+					assertScores(t, wg, pairBetweenness(wg, nil, endpoints), scores)
+				}
+				assertScores(t, g, pairBetweenness(g, nil, endpoints), BetweennessCentrality(g, opts))
+			}
+		}
+	}
+}
+
+func TestBetweennessCentralityZeroWeightEdge(t *testing.T) {
+	// the path 0-1-2-3 with a zero-weight middle edge, which is tight in both directions.
+	g, err := NewWeighted([]uint32{0, 1, 2}, []uint32{1, 2, 3}, []float32{1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertScores(t, g, []float64{0, 2, 2, 0}, BetweennessCentrality(g, BetweennessOptions{}))
+	assertScores(t, g, []float64{3, 5, 5, 3}, BetweennessCentrality(g, BetweennessOptions{Endpoints: true}))
+}
+
+func assertScores(t *testing.T, g Graph, expected, actual []float64) {
+	t.Helper()
+	for v := range expected {
+		if math.Abs(expected[v]-actual[v]) > 1e-6 {
+			t.Fatalf("%v: score of %d is %v, expected %v", g, v, actual[v], expected[v])
+		}
+	}
+}
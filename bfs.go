@@ -9,6 +9,9 @@ import (
 
 const unvisited = math.MaxUint32
 
+// NoParent is the parent of a search source and of vertices not reached by a search.
+const NoParent = ^u0
+
 // BFS computes a vector of levels from src and returns a vector
 // of vertices visited in order along with a vector of distances
 // indexed by vertex.
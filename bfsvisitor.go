@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"github.com/sbromberger/bitvec"
+	"github.com/shawnsmithdev/zermelo/zuint32"
+)
+
+// NoDepthLimit may be passed as the maximum depth of VisitBFS to search the entire graph.
+const NoDepthLimit = ^u0
+
+// BFSAction tells a visitor-driven BFS how to proceed.
+type BFSAction uint8
+
+const (
+	// BFSContinue continues the search.
+	BFSContinue BFSAction = iota
+	// BFSSkip prunes the search. Returned from OnDiscover, the vertex is recorded but its
+	// out neighbors are not examined; returned from OnExamineEdge, the edge is not followed.
+	// Returned from OnFinishLevel, it is the same as BFSContinue.
+	BFSSkip
+	// BFSStop ends the search immediately.
+	BFSStop
+)
+
+// BFSVisitor receives events from VisitBFS.
+type BFSVisitor interface {
+	// OnDiscover is called when v is first reached, with its level.
+	OnDiscover(v, level uint32) BFSAction
+	// OnExamineEdge is called for each out edge of an expanded vertex u, before v is
+	// checked for a previous visit.
+	OnExamineEdge(u, v uint32) BFSAction
+	// OnFinishLevel is called when all vertices at level have been discovered, with those
+	// vertices in order of discovery.
+	OnFinishLevel(level uint32, vertices []uint32) BFSAction
+}
+
+// NullBFSVisitor is a BFSVisitor that continues on all events. Embed it in a type to
+// implement only some events.
+type NullBFSVisitor struct{}
+
+func (NullBFSVisitor) OnDiscover(v, level uint32) BFSAction                    { return BFSContinue }
+func (NullBFSVisitor) OnExamineEdge(u, v uint32) BFSAction                     { return BFSContinue }
+func (NullBFSVisitor) OnFinishLevel(level uint32, vertices []uint32) BFSAction { return BFSContinue }
+
+// VisitBFS computes a vector of levels from src as BFS does, reporting events to vis, which
+// may prune or stop the search. Vertices more than maxDepth levels from src are not visited.
+// It returns a vector of vertices visited in order, a vector of levels indexed by vertex and
+// a vector of BFS parents indexed by vertex. The parent of src and of unreached vertices is
+// NoParent. If the search is stopped, the results so far are returned. vis may be nil.
+func VisitBFS(g Graph, src uint32, vis BFSVisitor, maxDepth uint32) (vertexList, vertLevel, parents []uint32) {
+	if vis == nil {
+		vis = NullBFSVisitor{}
+	}
+	nv := g.NumVertices()
+	vertLevel = make([]uint32, nv)
+	parents = make([]uint32, nv)
+	for i := uint32(0); i < nv; i++ {
+		vertLevel[i] = unvisited
+		parents[i] = NoParent
+	}
+
+	visited := bitvec.NewBitVec(nv)
+	curLevel := make([]uint32, 0, nv)
+	nextLevel := make([]uint32, 0, nv)
+	nLevel := uint32(1)
+	vertLevel[src] = 0
+	visited.TrySet(src)
+	vertexList = make([]uint32, 0, nv)
+	vertexList = append(vertexList, src)
+
+	switch vis.OnDiscover(src, 0) {
+	case BFSStop:
+		return vertexList, vertLevel, parents
+	case BFSContinue:
+		curLevel = append(curLevel, src)
+	}
+	if vis.OnFinishLevel(0, vertexList) == BFSStop {
+		return vertexList, vertLevel, parents
+	}
+
+	for len(curLevel) > 0 && nLevel <= maxDepth {
+		levelStart := len(vertexList)
+		for _, u := range curLevel {
+			for _, v := range g.OutNeighbors(u) {
+				switch vis.OnExamineEdge(u, v) {
+				case BFSStop:
+					return vertexList, vertLevel, parents
+				case BFSSkip:
+					continue
+				}
+				if !visited.TrySet(v) {
+					continue
+				}
+				vertLevel[v] = nLevel
+				parents[v] = u
+				vertexList = append(vertexList, v)
+				switch vis.OnDiscover(v, nLevel) {
+				case BFSStop:
+					return vertexList, vertLevel, parents
+				case BFSContinue:
+					nextLevel = append(nextLevel, v)
+				}
+			}
+		}
+		if len(vertexList) > levelStart && vis.OnFinishLevel(nLevel, vertexList[levelStart:]) == BFSStop {
+			return vertexList, vertLevel, parents
+		}
+		nLevel++
+		curLevel = curLevel[:0]
+		curLevel, nextLevel = nextLevel, curLevel
+		zuint32.SortBYOB(curLevel, nextLevel[:nv])
+	}
+	return vertexList, vertLevel, parents
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+// scriptedBFSVisitor returns the actions in its maps for the matching events, and records the
+// vertices of each level.
+type scriptedBFSVisitor struct {
+	discover map[uint32]BFSAction
+	examine  map[[2]uint32]BFSAction
+	levels   [][]uint32
+}
+
+func (vis *scriptedBFSVisitor) OnDiscover(v, level uint32) BFSAction {
+	return vis.discover[v]
+}
+
+func (vis *scriptedBFSVisitor) OnExamineEdge(u, v uint32) BFSAction {
+	return vis.examine[[2]uint32{u, v}]
+}
+
+func (vis *scriptedBFSVisitor) OnFinishLevel(level uint32, vertices []uint32) BFSAction {
+	vis.levels = append(vis.levels, append([]uint32{}, vertices...))
+	return BFSContinue
+}
+
+func TestVisitBFS(t *testing.T) {
+	// the diamond 0 -> {1, 2} -> 3 -> 4.
+	g, err := NewDiGraph([]uint32{0, 0, 1, 2, 3}, []uint32{1, 2, 3, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                 string
+		vis                  *scriptedBFSVisitor
+		maxDepth             uint32
+		list, level, parents []uint32
+		levels               [][]uint32
+	}{
+		{
+			"all", &scriptedBFSVisitor{}, NoDepthLimit,
+			[]uint32{0, 1, 2, 3, 4}, []uint32{0, 1, 1, 2, 3}, []uint32{NoParent, 0, 0, 1, 3},
+			[][]uint32{{0}, {1, 2}, {3}, {4}},
+		},
+		{
+			"depth 2", &scriptedBFSVisitor{}, 2,
+			[]uint32{0, 1, 2, 3}, []uint32{0, 1, 1, 2, unvisited}, []uint32{NoParent, 0, 0, 1, NoParent},
+			[][]uint32{{0}, {1, 2}, {3}},
+		},
+		{
+			"skip vertex", &scriptedBFSVisitor{discover: map[uint32]BFSAction{1: BFSSkip}}, NoDepthLimit,
+			[]uint32{0, 1, 2, 3, 4}, []uint32{0, 1, 1, 2, 3}, []uint32{NoParent, 0, 0, 2, 3},
+			[][]uint32{{0}, {1, 2}, {3}, {4}},
+		},
+		{
+			"skip edge", &scriptedBFSVisitor{examine: map[[2]uint32]BFSAction{{0, 1}: BFSSkip}}, NoDepthLimit,
+			[]uint32{0, 2, 3, 4}, []uint32{0, unvisited, 1, 2, 3}, []uint32{NoParent, NoParent, 0, 2, 3},
+			[][]uint32{{0}, {2}, {3}, {4}},
+		},
+		{
+			"stop", &scriptedBFSVisitor{discover: map[uint32]BFSAction{2: BFSStop}}, NoDepthLimit,
+			[]uint32{0, 1, 2}, []uint32{0, 1, 1, unvisited, unvisited}, []uint32{NoParent, 0, 0, NoParent, NoParent},
+			[][]uint32{{0}},
+		},
+	}
+	for _, tt := range tests {
+		list, level, parents := VisitBFS(g, 0, tt.vis, tt.maxDepth)
+		if !reflect.DeepEqual(list, tt.list) || !reflect.DeepEqual(level, tt.level) || !reflect.DeepEqual(parents, tt.parents) {
+			t.Errorf("%s: VisitBFS = %v, %v, %v, expected %v, %v, %v", tt.name, list, level, parents, tt.list, tt.level, tt.parents)
+		}
+		if !reflect.DeepEqual(tt.vis.levels, tt.levels) {
+			t.Errorf("%s: levels finished = %v, expected %v", tt.name, tt.vis.levels, tt.levels)
+		}
+	}
+}
+
+func TestVisitBFSMatchesBFS(t *testing.T) {
+	for _, g := range randomGraphs(t, 9, 40, 100) {
+		wantList, wantLevel := BFS(g, 0)
+		list, level, parents := VisitBFS(g, 0, nil, NoDepthLimit)
+		if !reflect.DeepEqual(level, wantLevel) || !reflect.DeepEqual(levelSets(list, level), levelSets(wantList, wantLevel)) {
+			t.Fatalf("%v: VisitBFS = %v, %v, expected %v, %v", g, list, level, wantList, wantLevel)
+		}
+		for v, p := range parents {
+			if v != 0 && level[v] != unvisited && (level[p]+1 != level[v] || !g.HasEdge(p, uint32(v))) {
+				t.Fatalf("%v: parent of %d is %d", g, v, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/sbromberger/graphmatrix"
+)
+
+// bccFrame is a vertex on the DFS path of biconnected, the position in the forward matrix of the
+// tree edge that led to it, and the position of its next edge to examine.
+type bccFrame struct {
+	u       uint32
+	treePos uint64
+	next    uint64
+}
+
+// bccResult holds the result of biconnected.
+type bccResult struct {
+	articulation []bool
+	bridges      []SimpleEdge
+	labels       []uint32
+	count        uint32
+}
+
+// biconnected runs an iterative Hopcroft-Tarjan depth-first search over the forward matrix of g, so
+// it has no recursion depth limit. If withLabels is true, each edge position is labeled with its
+// biconnected component.
+func biconnected(g SimpleGraph, withLabels bool) bccResult {
+	mx := g.FMat()
+	nv := g.NumVertices()
+	disc := make([]uint32, nv)
+	low := make([]uint32, nv)
+	for i := range disc {
+		disc[i] = unvisited
+	}
+	res := bccResult{articulation: make([]bool, nv), bridges: []SimpleEdge{}}
+	var edges []uint64 // positions of tree and back edges not yet assigned to a component
+	if withLabels {
+		res.labels = make([]uint32, len(mx.Indices))
+		for i := range res.labels {
+			res.labels[i] = unvisited
+		}
+	}
+
+	frames := []bccFrame{}
+	t := u0
+	for root := u0; root < nv; root++ {
+		if disc[root] != unvisited {
+			continue
+		}
+		disc[root], low[root] = t, t
+		t++
+		frames = append(frames, bccFrame{u: root, next: mx.IndPtr[root]})
+		rootChildren := 0
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			u := top.u
+			if top.next < mx.IndPtr[u+1] {
+				pos := top.next
+				top.next++
+				v := mx.Indices[pos]
+				parent := NoParent
+				if len(frames) > 1 {
+					parent = frames[len(frames)-2].u
+				}
+				switch {
+				case v == u || v == parent:
+					// self loops are in no component, and the tree edge is not a back edge.
+				case disc[v] == unvisited:
+					disc[v], low[v] = t, t
+					t++
+					if withLabels {
+						edges = append(edges, pos)
+					}
+					if u == root {
+						rootChildren++
+					}
+					frames = append(frames, bccFrame{u: v, treePos: pos, next: mx.IndPtr[v]})
+				case disc[v] < disc[u]:
+					if withLabels {
+						edges = append(edges, pos)
+					}
+					if disc[v] < low[u] {
+						low[u] = disc[v]
+					}
+				}
+				continue
+			}
+
+			treePos := top.treePos
+			frames = frames[:len(frames)-1]
+			if len(frames) == 0 {
+				break
+			}
+			p := frames[len(frames)-1].u
+			if low[u] < low[p] {
+				low[p] = low[u]
+			}
+			if low[u] >= disc[p] {
+				// p separates the subtree of u from the rest of the graph.
+				if p != root {
+					res.articulation[p] = true
+				}
+				if withLabels {
+					for {
+						pos := edges[len(edges)-1]
+						edges = edges[:len(edges)-1]
+						res.labels[pos] = res.count
+						if pos == treePos {
+							break
+						}
+					}
+					res.count++
+				}
+			}
+			if low[u] > disc[p] {
+				res.bridges = append(res.bridges, SimpleEdge{p, u})
+			}
+		}
+		if rootChildren > 1 {
+			res.articulation[root] = true
+		}
+	}
+
+	if withLabels {
+		// each edge was labeled in the direction it was examined; copy the label to its reverse.
+		for u := u0; u < nv; u++ {
+			for pos := mx.IndPtr[u]; pos < mx.IndPtr[u+1]; pos++ {
+				v := mx.Indices[pos]
+				if v == u || res.labels[pos] != unvisited {
+					continue
+				}
+				if rev, found := graphmatrix.SearchSorted32(mx.Indices, u, mx.IndPtr[v], mx.IndPtr[v+1]); found {
+					res.labels[pos] = res.labels[rev]
+				}
+			}
+		}
+	}
+	return res
+}
+
+// ArticulationPoints returns the vertices of g whose removal increases the number of connected
+// components, in increasing order.
+func ArticulationPoints(g SimpleGraph) []uint32 {
+	res := biconnected(g, false)
+	points := []uint32{}
+	for v, a := range res.articulation {
+		if a {
+			points = append(points, uint32(v))
+		}
+	}
+	return points
+}
+
+// Bridges returns the edges of g whose removal increases the number of connected components, with
+// the lower vertex as the source, in order of source and destination.
+func Bridges(g SimpleGraph) []SimpleEdge {
+	bridges := biconnected(g, false).bridges
+	for i, e := range bridges {
+		if e.src > e.dst {
+			bridges[i] = SimpleEdge{e.dst, e.src}
+		}
+	}
+	sort.Slice(bridges, func(i, j int) bool {
+		if bridges[i].src != bridges[j].src {
+			return bridges[i].src < bridges[j].src
+		}
+		return bridges[i].dst < bridges[j].dst
+	})
+	return bridges
+}
+
+// BiconnectedComponents labels each edge of g with its biconnected component, parallel to
+// g.FMat().Indices, and returns the labels and the number of components. Self loops have a label of
+// math.MaxUint32.
+func BiconnectedComponents(g SimpleGraph) (labels []uint32, count uint32) {
+	res := biconnected(g, true)
+	return res.labels, res.count
+}
@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// componentsWithout returns the connected component labels of g after removing the edges at x,
+// if x is not NoParent, and the edge between a and b, and the number of components other than x.
+func componentsWithout(t *testing.T, g SimpleGraph, x, a, b uint32) ([]uint32, int) {
+	ss, ds := []uint32{}, []uint32{}
+	for u := u0; u < g.NumVertices(); u++ {
+		for _, v := range g.OutNeighbors(u) {
+			if u > v || u == x || v == x || (u == a && v == b) || (u == b && v == a) {
+				continue
+			}
+			ss, ds = append(ss, u), append(ds, v)
+		}
+	}
+	h, err := newGraph(g.NumVertices(), ss, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels, sizes := ConnectedComponents(h)
+	if x != NoParent {
+		return labels, len(sizes) - 1
+	}
+	return labels, len(sizes)
+}
+
+// edgeBlocks returns the root of the biconnected component of each edge position of g.FMat(),
+// found by joining the edges w-u and w-v whenever u and v stay connected without w, and
+// math.MaxUint32 for self loops.
+func edgeBlocks(t *testing.T, g SimpleGraph) []uint32 {
+	mx := g.FMat()
+	parent := make([]uint32, len(mx.Indices))
+	for i := range parent {
+		parent[i] = uint32(i)
+	}
+	var find func(p uint32) uint32
+	find = func(p uint32) uint32 {
+		if parent[p] != p {
+			parent[p] = find(parent[p])
+		}
+		return parent[p]
+	}
+	union := func(p, q uint32) { parent[find(p)] = find(q) }
+	position := func(u, v uint32) uint32 {
+		for p := mx.IndPtr[u]; p < mx.IndPtr[u+1]; p++ {
+			if mx.Indices[p] == v {
+				return uint32(p)
+			}
+		}
+		t.Fatalf("%v has no edge %d-%d", g, u, v)
+		return 0
+	}
+
+	for w := u0; w < g.NumVertices(); w++ {
+		labels, _ := componentsWithout(t, g, w, NoParent, NoParent)
+		for p := mx.IndPtr[w]; p < mx.IndPtr[w+1]; p++ {
+			u := mx.Indices[p]
+			if u == w {
+				continue
+			}
+			union(uint32(p), position(u, w))
+			for q := p + 1; q < mx.IndPtr[w+1]; q++ {
+				if v := mx.Indices[q]; v != w && labels[u] == labels[v] {
+					union(uint32(p), uint32(q))
+				}
+			}
+		}
+	}
+	blocks := make([]uint32, len(parent))
+	for u := u0; u < g.NumVertices(); u++ {
+		for p := mx.IndPtr[u]; p < mx.IndPtr[u+1]; p++ {
+			blocks[p] = find(uint32(p))
+			if mx.Indices[p] == u {
+				blocks[p] = math.MaxUint32
+			}
+		}
+	}
+	return blocks
+}
+
+func TestBiconnectedComponents(t *testing.T) {
+	// the triangles 0-1-2 and 3-4-5 joined by the bridge 2-3, and 6 with a self loop.
+	g, err := New([]uint32{0, 1, 2, 2, 3, 4, 5, 6}, []uint32{1, 2, 0, 3, 4, 5, 3, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points := ArticulationPoints(g); !reflect.DeepEqual(points, []uint32{2, 3}) {
+		t.Errorf("ArticulationPoints = %v, expected [2 3]", points)
+	}
+	if bridges := Bridges(g); !reflect.DeepEqual(bridges, []SimpleEdge{{2, 3}}) {
+		t.Errorf("Bridges = %v, expected [2 -> 3]", bridges)
+	}
+	labels, count := BiconnectedComponents(g)
+	if count != 3 {
+		t.Fatalf("BiconnectedComponents found %d components, expected 3", count)
+	}
+	mx := g.FMat()
+	block := map[[2]uint32]int{{0, 1}: 0, {0, 2}: 0, {1, 2}: 0, {2, 3}: 1, {3, 4}: 2, {3, 5}: 2, {4, 5}: 2}
+	first := map[int]uint32{}
+	for u := u0; u < g.NumVertices(); u++ {
+		for p := mx.IndPtr[u]; p < mx.IndPtr[u+1]; p++ {
+			v := mx.Indices[p]
+			if u == v {
+				if labels[p] != math.MaxUint32 {
+					t.Errorf("self loop %d-%d has label %d", u, v, labels[p])
+				}
+				continue
+			}
+			key := [2]uint32{u, v}
+			if u > v {
+				key = [2]uint32{v, u}
+			}
+			b := block[key]
+			if _, ok := first[b]; !ok {
+				first[b] = labels[p]
+			}
+			if labels[p] != first[b] {
+				t.Errorf("edge %d-%d has label %d, expected %d", u, v, labels[p], first[b])
+			}
+		}
+	}
+	if first[0] == first[1] || first[1] == first[2] || first[0] == first[2] {
+		t.Errorf("BiconnectedComponents labels = %v, expected three components", labels)
+	}
+}
+
+func TestBiconnectedComponentsMatchesRemoval(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for i := 0; i < 100; i++ {
+		nv := 1 + r.Intn(12)
+		ss, ds, _ := randomEdges(r, nv, r.Intn(2*nv))
+		g, err := New(ss, ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, base := componentsWithout(t, g, NoParent, NoParent, NoParent)
+
+		points := []uint32{}
+		bridges := []SimpleEdge{}
+		for u := u0; u < g.NumVertices(); u++ {
+			if _, n := componentsWithout(t, g, u, NoParent, NoParent); n > base {
+				points = append(points, u)
+			}
+			for _, v := range g.OutNeighbors(u) {
+				if _, n := componentsWithout(t, g, NoParent, u, v); u < v && n > base {
+					bridges = append(bridges, SimpleEdge{u, v})
+				}
+			}
+		}
+		if got := ArticulationPoints(g); !reflect.DeepEqual(got, points) {
+			t.Fatalf("%v: ArticulationPoints = %v, expected %v", g, got, points)
+		}
+		if got := Bridges(g); !reflect.DeepEqual(got, bridges) {
+			t.Fatalf("%v: Bridges = %v, expected %v", g, got, bridges)
+		}
+
+		labels, count := BiconnectedComponents(g)
+		blocks := edgeBlocks(t, g)
+		// labels and blocks must partition the edges in the same way.
+		toBlock, toLabel := map[uint32]uint32{}, map[uint32]uint32{}
+		for p, l := range labels {
+			b := blocks[p]
+			if (l == math.MaxUint32) != (b == math.MaxUint32) || (l != math.MaxUint32 && l >= count) {
+				t.Fatalf("%v: edge position %d has label %d of %d", g, p, l, count)
+			}
+			if l == math.MaxUint32 {
+				continue
+			}
+			if bb, ok := toBlock[l]; ok && bb != b {
+				t.Fatalf("%v: BiconnectedComponents = %v, splitting the component of edge position %d", g, labels, p)
+			}
+			if ll, ok := toLabel[b]; ok && ll != l {
+				t.Fatalf("%v: BiconnectedComponents = %v, joining the component of edge position %d", g, labels, p)
+			}
+			toBlock[l], toLabel[b] = b, l
+		}
+		if len(toBlock) != int(count) {
+			t.Fatalf("%v: BiconnectedComponents found %d components, expected %d", g, count, len(toBlock))
+		}
+	}
+}
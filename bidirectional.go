@@ -0,0 +1,80 @@
+package graph
+
+// BidirectionalBFS returns a shortest path from src to dst as a vector of vertices starting
+// with src and ending with dst, and true, or nil and false if dst is not reachable from src.
+// It searches forward from src over out neighbors and backward from dst over in neighbors,
+// expanding whichever frontier has fewer edges to examine, and stops when the searches meet.
+// Only the vertices reached by the two searches are stored, so it touches a small part of
+// most graphs.
+func BidirectionalBFS(g Graph, src, dst uint32) ([]uint32, bool) {
+	if src == dst {
+		return []uint32{src}, true
+	}
+	// fwdParents maps each vertex reached from src to its parent, and bwdParents maps each
+	// vertex that reaches dst to its successor on the way to dst.
+	fwdParents := map[uint32]uint32{src: src}
+	bwdParents := map[uint32]uint32{dst: dst}
+	fwdLevel := []uint32{src}
+	bwdLevel := []uint32{dst}
+	var next []uint32
+
+	for len(fwdLevel) > 0 && len(bwdLevel) > 0 {
+		fwdEdges, bwdEdges := uint64(0), uint64(0)
+		for _, u := range fwdLevel {
+			fwdEdges += uint64(g.OutDegree(u))
+		}
+		for _, u := range bwdLevel {
+			bwdEdges += uint64(g.InDegree(u))
+		}
+
+		next = next[:0]
+		if fwdEdges <= bwdEdges {
+			for _, u := range fwdLevel {
+				for _, v := range g.OutNeighbors(u) {
+					if _, seen := fwdParents[v]; seen {
+						continue
+					}
+					fwdParents[v] = u
+					if _, met := bwdParents[v]; met {
+						return joinPaths(v, src, dst, fwdParents, bwdParents), true
+					}
+					next = append(next, v)
+				}
+			}
+			fwdLevel, next = next, fwdLevel
+		} else {
+			for _, u := range bwdLevel {
+				for _, v := range g.InNeighbors(u) {
+					if _, seen := bwdParents[v]; seen {
+						continue
+					}
+					bwdParents[v] = u
+					if _, met := fwdParents[v]; met {
+						return joinPaths(v, src, dst, fwdParents, bwdParents), true
+					}
+					next = append(next, v)
+				}
+			}
+			bwdLevel, next = next, bwdLevel
+		}
+	}
+	return nil, false
+}
+
+// joinPaths returns the path from src to dst through the vertex meet at which the forward and
+// backward searches of BidirectionalBFS met.
+func joinPaths(meet, src, dst uint32, fwdParents, bwdParents map[uint32]uint32) []uint32 {
+	path := []uint32{}
+	for v := meet; v != src; v = fwdParents[v] {
+		path = append(path, v)
+	}
+	path = append(path, src)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	for v := meet; v != dst; {
+		v = bwdParents[v]
+		path = append(path, v)
+	}
+	return path
+}
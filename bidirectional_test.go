@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBidirectionalBFS(t *testing.T) {
+	// 0 -> 1 -> 2 -> 3 and the shortcut 0 -> 4 -> 3.
+	g, err := NewDiGraph([]uint32{0, 1, 2, 0, 4}, []uint32{1, 2, 3, 4, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, ok := BidirectionalBFS(g, 0, 3); !ok || !reflect.DeepEqual(path, []uint32{0, 4, 3}) {
+		t.Errorf("BidirectionalBFS(0, 3) = %v, %v, expected [0 4 3], true", path, ok)
+	}
+	if path, ok := BidirectionalBFS(g, 3, 0); ok || path != nil {
+		t.Errorf("BidirectionalBFS(3, 0) = %v, %v, expected nil, false", path, ok)
+	}
+	if path, ok := BidirectionalBFS(g, 2, 2); !ok || !reflect.DeepEqual(path, []uint32{2}) {
+		t.Errorf("BidirectionalBFS(2, 2) = %v, %v, expected [2], true", path, ok)
+	}
+}
+
+func TestBidirectionalBFSMatchesBFS(t *testing.T) {
+	for _, g := range randomGraphs(t, 11, 40, 60) {
+		for src := u0; src < g.NumVertices(); src += 3 {
+			_, level := BFS(g, src)
+			for dst := u0; dst < g.NumVertices(); dst++ {
+				path, ok := BidirectionalBFS(g, src, dst)
+				if ok != (level[dst] != unvisited) {
+					t.Fatalf("%v: BidirectionalBFS(%d, %d) found %v, expected %v", g, src, dst, ok, !ok)
+				}
+				if !ok {
+					continue
+				}
+				if uint32(len(path)-1) != level[dst] || path[0] != src || path[len(path)-1] != dst {
+					t.Fatalf("%v: BidirectionalBFS(%d, %d) = %v, expected a path of %d edges", g, src, dst, path, level[dst])
+				}
+				for i := 1; i < len(path); i++ {
+					if !g.HasEdge(path[i-1], path[i]) {
+						t.Fatalf("%v: BidirectionalBFS(%d, %d) = %v, which has no edge %d -> %d", g, src, dst, path, path[i-1], path[i])
+					}
+				}
+			}
+		}
+	}
+}
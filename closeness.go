@@ -0,0 +1,122 @@
+package graph
+
+// distanceSums holds, for each vertex v, the number of other sources that reach v, the sum of
+// their distances to v and the sum of the inverses of those distances.
+type distanceSums struct {
+	reached []uint32
+	total   []float64
+	inverse []float64
+}
+
+func newDistanceSums(nv uint32) distanceSums {
+	return distanceSums{
+		reached: make([]uint32, nv),
+		total:   make([]float64, nv),
+		inverse: make([]float64, nv),
+	}
+}
+
+// sumDistances runs BFS from each of srcs, using `procs` goroutines, and sums the distances
+// from the sources to each vertex. Each goroutine sums into its own distanceSums.
+func sumDistances(g Graph, srcs []uint32, procs int) distanceSums {
+	nv := g.NumVertices()
+	if procs < 1 {
+		procs = 1
+	}
+	workers := make([]distanceSums, procs)
+	parallelFor(uint32(len(srcs)), procs, func(worker int, i uint32) {
+		sums := &workers[worker]
+		if sums.reached == nil {
+			*sums = newDistanceSums(nv)
+		}
+		vertexList, vertLevel := BFS(g, srcs[i])
+		for _, v := range vertexList[1:] {
+			d := float64(vertLevel[v])
+			sums.reached[v]++
+			sums.total[v] += d
+			sums.inverse[v] += 1 / d
+		}
+	})
+
+	sums := newDistanceSums(nv)
+	for _, w := range workers {
+		if w.reached == nil {
+			continue
+		}
+		for v := range sums.reached {
+			sums.reached[v] += w.reached[v]
+			sums.total[v] += w.total[v]
+			sums.inverse[v] += w.inverse[v]
+		}
+	}
+	return sums
+}
+
+// closeness computes closeness centrality from the distances of `nsrcs` sources to each vertex.
+func closeness(sums distanceSums, nv uint32, nsrcs int) []float64 {
+	scores := make([]float64, nv)
+	if nv < 2 {
+		return scores
+	}
+	scale := float64(nv) / float64(nsrcs) // 1 unless sampled
+	for v, r := range sums.reached {
+		if r == 0 {
+			continue
+		}
+		reached := float64(r) * scale
+		scores[v] = (reached / float64(nv-1)) * (float64(r) / sums.total[v])
+	}
+	return scores
+}
+
+// ClosenessCentrality computes the closeness centrality of every vertex v, the inverse of the
+// average distance in hops to v from the vertices that reach it, with BFS from every vertex
+// using `procs` goroutines, and returns a vector of scores indexed by vertex.
+// So that graphs that are not connected are handled, the scores are scaled by the fraction of
+// other vertices that reach v (the Wasserman-Faust normalization); a vertex that no other vertex
+// reaches has a score of 0.
+func ClosenessCentrality(g Graph, procs int) []float64 {
+	nv := g.NumVertices()
+	srcs := sampleSources(nv, 0, 0)
+	return closeness(sumDistances(g, srcs, procs), nv, len(srcs))
+}
+
+// SampledClosenessCentrality estimates the closeness centrality of every vertex as
+// ClosenessCentrality does, with BFS from `samples` vertices chosen at random with `seed`, and
+// returns a vector of scores indexed by vertex. It takes time proportional to `samples` rather
+// than NumVertices.
+func SampledClosenessCentrality(g Graph, samples int, seed int64, procs int) []float64 {
+	nv := g.NumVertices()
+	srcs := sampleSources(nv, samples, seed)
+	return closeness(sumDistances(g, srcs, procs), nv, len(srcs))
+}
+
+// harmonic computes harmonic centrality from the distances of `nsrcs` sources to each vertex.
+func harmonic(sums distanceSums, nv uint32, nsrcs int) []float64 {
+	scale := float64(nv) / float64(nsrcs) // 1 unless sampled
+	scores := sums.inverse
+	for v := range scores {
+		scores[v] *= scale
+	}
+	return scores
+}
+
+// HarmonicCentrality computes the harmonic centrality of every vertex v, the sum of the
+// inverses of the distances in hops to v from every other vertex, with BFS from every vertex
+// using `procs` goroutines, and returns a vector of scores indexed by vertex. Vertices that do
+// not reach v contribute 0, so graphs that are not connected need no special handling.
+func HarmonicCentrality(g Graph, procs int) []float64 {
+	nv := g.NumVertices()
+	srcs := sampleSources(nv, 0, 0)
+	return harmonic(sumDistances(g, srcs, procs), nv, len(srcs))
+}
+
+// SampledHarmonicCentrality estimates the harmonic centrality of every vertex as
+// HarmonicCentrality does, with BFS from `samples` vertices chosen at random with `seed`, and
+// returns a vector of scores indexed by vertex. It takes time proportional to `samples` rather
+// than NumVertices.
+func SampledHarmonicCentrality(g Graph, samples int, seed int64, procs int) []float64 {
+	nv := g.NumVertices()
+	srcs := sampleSources(nv, samples, seed)
+	return harmonic(sumDistances(g, srcs, procs), nv, len(srcs))
+}
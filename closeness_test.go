@@ -0,0 +1,65 @@
+package graph
+
+import "testing"
+
+// bfsCentrality computes closeness and harmonic centrality from the BFS levels from each of srcs.
+func bfsCentrality(g Graph, srcs []uint32) (closeness, harmonic []float64) {
+	nv := g.NumVertices()
+	reached := make([]float64, nv)
+	total := make([]float64, nv)
+	harmonic = make([]float64, nv)
+	for _, src := range srcs {
+		_, vertLevel := BFS(g, src)
+		for v, level := range vertLevel {
+			if uint32(v) != src && level != unvisited {
+				reached[v]++
+				total[v] += float64(level)
+				harmonic[v] += 1 / float64(level)
+			}
+		}
+	}
+	closeness = make([]float64, nv)
+	scale := float64(nv) / float64(len(srcs))
+	for v := range closeness {
+		harmonic[v] *= scale
+		if reached[v] > 0 {
+			closeness[v] = reached[v] * scale / float64(nv-1) * reached[v] / total[v]
+		}
+	}
+	return closeness, harmonic
+}
+
+func TestClosenessCentrality(t *testing.T) {
+	// the path 0 -> 1 -> 2 and the isolated vertex 3.
+	g, err := NewDiGraph([]uint32{0, 1, 3}, []uint32{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertScores(t, g, []float64{0, 1.0 / 3, 4.0 / 9, 0}, ClosenessCentrality(g, 2))
+	assertScores(t, g, []float64{0, 1, 1.5, 0}, HarmonicCentrality(g, 2))
+
+	// a star with center 0.
+	star, err := New([]uint32{0, 0, 0}, []uint32{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertScores(t, star, []float64{1, 0.6, 0.6, 0.6}, ClosenessCentrality(star, 1))
+	assertScores(t, star, []float64{3, 2, 2, 2}, HarmonicCentrality(star, 1))
+}
+
+func TestClosenessCentralityMatchesBFS(t *testing.T) {
+	for _, g := range randomGraphs(t, 18, 30, 40) {
+		nv := g.NumVertices()
+		all := sampleSources(nv, 0, 0)
+		closeness, harmonic := bfsCentrality(g, all)
+		samples := 1 + int(nv)/3
+		srcs := sampleSources(nv, samples, 18)
+		sampledCloseness, sampledHarmonic := bfsCentrality(g, srcs)
+		forProcs(func(procs int) {
+			assertScores(t, g, closeness, ClosenessCentrality(g, procs))
+			assertScores(t, g, harmonic, HarmonicCentrality(g, procs))
+			assertScores(t, g, sampledCloseness, SampledClosenessCentrality(g, samples, 18, procs))
+			assertScores(t, g, sampledHarmonic, SampledHarmonicCentrality(g, samples, 18, procs))
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// afforestRounds is the number of neighbors of each vertex linked before the largest
+// component is sampled, and afforestSamples the number of vertices sampled.
+const (
+	afforestRounds  = 2
+	afforestSamples = 1024
+)
+
+// relabel converts a vector of component representatives indexed by vertex into component labels
+// numbered from 0 in order of the lowest vertex in each component, and the size of each component.
+func relabel(comp []uint32) (labels, sizes []uint32) {
+	labels = make([]uint32, len(comp))
+	ids := make([]uint32, len(comp))
+	for i := range ids {
+		ids[i] = unvisited
+	}
+	sizes = []uint32{}
+	for v, c := range comp {
+		if ids[c] == unvisited {
+			ids[c] = uint32(len(sizes))
+			sizes = append(sizes, 0)
+		}
+		labels[v] = ids[c]
+		sizes[ids[c]]++
+	}
+	return labels, sizes
+}
+
+// find returns the representative of the set holding v, halving the path to it.
+func find(parents []uint32, v uint32) uint32 {
+	for parents[v] != v {
+		parents[v] = parents[parents[v]]
+		v = parents[v]
+	}
+	return v
+}
+
+// ConnectedComponents computes the connected components of g, or its weakly connected components
+// if g is directed, with union-find. It returns a vector of component labels indexed by vertex,
+// numbered from 0 in order of the lowest vertex in each component, and a vector of the number of
+// vertices in each component indexed by label.
+func ConnectedComponents(g Graph) (labels, sizes []uint32) {
+	nv := g.NumVertices()
+	parents := make([]uint32, nv)
+	for i := range parents {
+		parents[i] = uint32(i)
+	}
+	directed := g.IsDirected()
+	for u := u0; u < nv; u++ {
+		for _, v := range g.OutNeighbors(u) {
+			if !directed && v > u { // each undirected edge is seen from both ends
+				break
+			}
+			ru, rv := find(parents, u), find(parents, v)
+			switch {
+			case ru < rv:
+				parents[rv] = ru
+			case rv < ru:
+				parents[ru] = rv
+			}
+		}
+	}
+	for v := u0; v < nv; v++ {
+		parents[v] = find(parents, v)
+	}
+	return relabel(parents)
+}
+
+// link joins the trees holding u and v in comp, pointing the higher root at the lower one.
+// It is safe to call concurrently.
+func link(comp []uint32, u, v uint32) {
+	p1 := atomic.LoadUint32(&comp[u])
+	p2 := atomic.LoadUint32(&comp[v])
+	for p1 != p2 {
+		high, low := p1, p2
+		if high < low {
+			high, low = low, high
+		}
+		pHigh := atomic.LoadUint32(&comp[high])
+		if pHigh == low || (pHigh == high && atomic.CompareAndSwapUint32(&comp[high], high, low)) {
+			return
+		}
+		p1 = atomic.LoadUint32(&comp[atomic.LoadUint32(&comp[high])])
+		p2 = atomic.LoadUint32(&comp[low])
+	}
+}
+
+// compress points every vertex in comp directly at the root of its tree, using `procs` goroutines.
+func compress(comp []uint32, procs int) {
+	blockIter(len(comp), procs, func(low, high int) {
+		for v := low; v < high; v++ {
+			for {
+				p := atomic.LoadUint32(&comp[v])
+				pp := atomic.LoadUint32(&comp[p])
+				if p == pp {
+					break
+				}
+				atomic.StoreUint32(&comp[v], pp)
+			}
+		}
+	})
+}
+
+// largestSampled returns the most frequent component representative among a random sample of
+// vertices of comp.
+func largestSampled(comp []uint32) uint32 {
+	r := rand.New(rand.NewSource(int64(len(comp))))
+	counts := make(map[uint32]int)
+	best, bestCount := comp[0], 0
+	for i := 0; i < afforestSamples; i++ {
+		c := comp[r.Intn(len(comp))]
+		counts[c]++
+		if counts[c] > bestCount {
+			best, bestCount = c, counts[c]
+		}
+	}
+	return best
+}
+
+// ParallelConnectedComponents computes the connected components of g, or its weakly connected
+// components if g is directed, using `procs` goroutines, and returns the same vectors as
+// ConnectedComponents. It uses the Afforest algorithm: it first links each vertex to a few of
+// its neighbors, which usually joins most of the graph into one component, then links the
+// remaining edges of the vertices outside the largest component, skipping the largest
+// component's edges.
+func ParallelConnectedComponents(g Graph, procs int) (labels, sizes []uint32) {
+	nv := g.NumVertices()
+	comp := make([]uint32, nv)
+	for i := range comp {
+		comp[i] = uint32(i)
+	}
+	if nv == 0 {
+		return relabel(comp)
+	}
+
+	for r := 0; r < afforestRounds; r++ {
+		blockIter(int(nv), procs, func(low, high int) {
+			for u := uint32(low); u < uint32(high); u++ {
+				if neighbors := g.OutNeighbors(u); r < len(neighbors) {
+					link(comp, u, neighbors[r])
+				}
+			}
+		})
+		compress(comp, procs)
+	}
+
+	largest := largestSampled(comp)
+	directed := g.IsDirected()
+	blockIter(int(nv), procs, func(low, high int) {
+		for u := uint32(low); u < uint32(high); u++ {
+			if atomic.LoadUint32(&comp[u]) == largest {
+				continue
+			}
+			neighbors := g.OutNeighbors(u)
+			for i := afforestRounds; i < len(neighbors); i++ {
+				link(comp, u, neighbors[i])
+			}
+			if directed {
+				// the in edges of u are out edges of vertices that may have been skipped.
+				for _, v := range g.InNeighbors(u) {
+					link(comp, u, v)
+				}
+			}
+		}
+	})
+	compress(comp, procs)
+	return relabel(comp)
+}
@@ -0,0 +1,37 @@
+package graph
+
+import "testing"
+
+func TestConnectedComponents(t *testing.T) {
+	// 0-1, 2-3-4 and 5 alone, undirected and with edges in one direction.
+	g, err := New([]uint32{0, 2, 3, 5}, []uint32{1, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg, err := NewDiGraph([]uint32{1, 3, 4, 5}, []uint32{0, 2, 3, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLabels, wantSizes := []uint32{0, 0, 1, 1, 1, 2}, []uint32{2, 3, 1}
+	for _, g := range []Graph{g, dg} {
+		labels, sizes := ConnectedComponents(g)
+		checkSame(t, g, "ConnectedComponents labels", 1, labels, wantLabels)
+		checkSame(t, g, "ConnectedComponents sizes", 1, sizes, wantSizes)
+		forProcs(func(procs int) {
+			labels, sizes := ParallelConnectedComponents(g, procs)
+			checkSame(t, g, "ParallelConnectedComponents labels", procs, labels, wantLabels)
+			checkSame(t, g, "ParallelConnectedComponents sizes", procs, sizes, wantSizes)
+		})
+	}
+}
+
+func TestParallelConnectedComponentsMatchesConnectedComponents(t *testing.T) {
+	for _, g := range parallelTestGraphs(t, 21) {
+		wantLabels, wantSizes := ConnectedComponents(g)
+		forProcs(func(procs int) {
+			labels, sizes := ParallelConnectedComponents(g, procs)
+			checkSame(t, g, "ParallelConnectedComponents labels", procs, labels, wantLabels)
+			checkSame(t, g, "ParallelConnectedComponents sizes", procs, sizes, wantSizes)
+		})
+	}
+}
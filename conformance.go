@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/sbromberger/graphmatrix"
+)
+
+// CheckGraph verifies that a Graph implementation is internally consistent and returns
+// an error describing the first inconsistency found. It checks that:
+//   - Vertices() returns each of 0 through NumVertices()-1 exactly once, in order;
+//   - neighbor lists are sorted, free of duplicates and within range;
+//   - OutDegree and InDegree agree with OutNeighbors and InNeighbors;
+//   - every out neighbor has the corresponding in neighbor, and HasEdge agrees with both;
+//   - undirected graphs have identical out and in neighbors;
+//   - Edges() returns NumEdges() edges, each of which exists (once, with Src() <= Dst(),
+//     for undirected graphs);
+//   - WeightedGraphs have a weight for every edge, stored identically in both directions.
+//
+// CheckGraph visits every edge several times and is intended for testing.
+func CheckGraph(g Graph) error {
+	nv := g.NumVertices()
+	directed := g.IsDirected()
+
+	n := u0
+	for it := g.Vertices(); !it.Done(); n++ {
+		if n >= nv {
+			return fmt.Errorf("Vertices() returned more than %d vertices", nv)
+		}
+		if v := it.Next(); v != n {
+			return fmt.Errorf("Vertices() returned %d, expected %d", v, n)
+		}
+	}
+	if n != nv {
+		return fmt.Errorf("Vertices() returned %d vertices, expected %d", n, nv)
+	}
+
+	var outSum, inSum, selfLoops uint64
+	for u := u0; u < nv; u++ {
+		outs := g.OutNeighbors(u)
+		ins := g.InNeighbors(u)
+		if err := checkNeighbors(outs, nv); err != nil {
+			return fmt.Errorf("OutNeighbors(%d): %v", u, err)
+		}
+		if err := checkNeighbors(ins, nv); err != nil {
+			return fmt.Errorf("InNeighbors(%d): %v", u, err)
+		}
+		if d := g.OutDegree(u); d != uint32(len(outs)) {
+			return fmt.Errorf("OutDegree(%d) = %d, but OutNeighbors(%d) has %d vertices", u, d, u, len(outs))
+		}
+		if d := g.InDegree(u); d != uint32(len(ins)) {
+			return fmt.Errorf("InDegree(%d) = %d, but InNeighbors(%d) has %d vertices", u, d, u, len(ins))
+		}
+		if !directed && !equalNeighbors(outs, ins) {
+			return fmt.Errorf("undirected graph has OutNeighbors(%d) = %v but InNeighbors(%d) = %v", u, outs, u, ins)
+		}
+		for _, v := range outs {
+			if !containsSorted(g.InNeighbors(v), u) {
+				return fmt.Errorf("%d is an out neighbor of %d, but %d is not an in neighbor of %d", v, u, u, v)
+			}
+			if !g.HasEdge(u, v) {
+				return fmt.Errorf("%d is an out neighbor of %d, but HasEdge(%d, %d) is false", v, u, u, v)
+			}
+			if u == v {
+				selfLoops++
+			}
+		}
+		for _, v := range ins {
+			if !containsSorted(g.OutNeighbors(v), u) {
+				return fmt.Errorf("%d is an in neighbor of %d, but %d is not an out neighbor of %d", v, u, u, v)
+			}
+		}
+		outSum += uint64(len(outs))
+		inSum += uint64(len(ins))
+	}
+	if outSum != inSum {
+		return fmt.Errorf("sum of out degrees (%d) differs from sum of in degrees (%d)", outSum, inSum)
+	}
+	ne := g.NumEdges()
+	expected := outSum
+	if !directed {
+		expected = (outSum + selfLoops) / 2
+	}
+	if ne != expected {
+		return fmt.Errorf("NumEdges() = %d, but neighbor lists contain %d edges", ne, expected)
+	}
+
+	n64 := uint64(0)
+	var prev Edge
+	for it := g.Edges(); !it.Done(); n64++ {
+		if n64 >= ne {
+			return fmt.Errorf("Edges() returned more than %d edges", ne)
+		}
+		e := it.Next()
+		if !g.HasEdge(e.Src(), e.Dst()) {
+			return fmt.Errorf("Edges() returned %d -> %d, but HasEdge is false", e.Src(), e.Dst())
+		}
+		if !directed && e.Src() > e.Dst() {
+			return fmt.Errorf("Edges() returned %d -> %d for an undirected graph; expected Src() <= Dst()", e.Src(), e.Dst())
+		}
+		if prev != nil && !(EdgeList{prev, e}).Less(0, 1) {
+			return fmt.Errorf("Edges() returned %d -> %d after %d -> %d", e.Src(), e.Dst(), prev.Src(), prev.Dst())
+		}
+		prev = e
+	}
+	if n64 != ne {
+		return fmt.Errorf("Edges() returned %d edges, expected %d", n64, ne)
+	}
+
+	if wg, ok := g.(WeightedGraph); ok {
+		return checkWeights(wg)
+	}
+	return nil
+}
+
+// checkWeights verifies the weights of a WeightedGraph.
+func checkWeights(g WeightedGraph) error {
+	for u := u0; u < g.NumVertices(); u++ {
+		vs, ws := g.OutEdgesWeighted(u)
+		if len(vs) != len(ws) {
+			return fmt.Errorf("OutEdgesWeighted(%d) returned %d neighbors and %d weights", u, len(vs), len(ws))
+		}
+		for i, v := range vs {
+			w, ok := g.Weight(u, v)
+			if !ok || w != ws[i] {
+				return fmt.Errorf("OutEdgesWeighted(%d) has weight %v for %d, but Weight(%d, %d) = %v, %v", u, ws[i], v, u, v, w, ok)
+			}
+		}
+		vs, ws = g.InEdgesWeighted(u)
+		if len(vs) != len(ws) {
+			return fmt.Errorf("InEdgesWeighted(%d) returned %d neighbors and %d weights", u, len(vs), len(ws))
+		}
+		for i, v := range vs {
+			w, ok := g.Weight(v, u)
+			if !ok || w != ws[i] {
+				return fmt.Errorf("InEdgesWeighted(%d) has weight %v for %d, but Weight(%d, %d) = %v, %v", u, ws[i], v, v, u, w, ok)
+			}
+		}
+	}
+	return nil
+}
+
+// checkNeighbors verifies that a neighbor list is strictly increasing and less than nv.
+func checkNeighbors(ns []uint32, nv uint32) error {
+	for i, v := range ns {
+		if v >= nv {
+			return fmt.Errorf("vertex %d out of range", v)
+		}
+		if i > 0 && ns[i-1] >= v {
+			return fmt.Errorf("not sorted or has duplicates at index %d", i)
+		}
+	}
+	return nil
+}
+
+func equalNeighbors(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsSorted returns true if the sorted vector ns contains v.
+func containsSorted(ns []uint32, v uint32) bool {
+	_, found := graphmatrix.SearchSorted32(ns, v, 0, uint64(len(ns)))
+	return found
+}
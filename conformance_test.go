@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// randomEdges returns ne random edges between nv vertices, with weights from 0 to 9, plus a self
+// loop on vertex nv-1 so that the graphs have nv vertices.
+func randomEdges(r *rand.Rand, nv, ne int) (ss, ds []uint32, ws []float32) {
+	for i := 0; i < ne; i++ {
+		ss = append(ss, uint32(r.Intn(nv)))
+		ds = append(ds, uint32(r.Intn(nv)))
+		ws = append(ws, float32(r.Intn(10)))
+	}
+	ss = append(ss, uint32(nv-1))
+	ds = append(ds, uint32(nv-1))
+	ws = append(ws, 1)
+	return ss, ds, ws
+}
+
+// randomGraphs returns n pairs of random undirected and directed weighted graphs of up to
+// maxNV vertices.
+func randomGraphs(t *testing.T, seed int64, n, maxNV int) []WeightedGraph {
+	r := rand.New(rand.NewSource(seed))
+	gs := []WeightedGraph{}
+	for i := 0; i < n; i++ {
+		nv := 1 + r.Intn(maxNV)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(4*nv))
+		g, err := NewWeighted(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dg, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs = append(gs, g, dg)
+	}
+	return gs
+}
+
+func checkGraph(t *testing.T, name string, g Graph) {
+	t.Helper()
+	if err := CheckGraph(g); err != nil {
+		t.Errorf("%s %v: %v", name, g, err)
+	}
+}
+
+func TestCheckGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	dir := t.TempDir()
+	for i := 0; i < 50; i++ {
+		nv := 1 + r.Intn(50)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(4*nv))
+		g, err := New(ss, ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dg, err := NewDiGraph(ss, ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg, err := NewWeighted(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wdg, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGraph(t, "SimpleGraph", g)
+		checkGraph(t, "SimpleDiGraph", dg)
+		checkGraph(t, "SimpleWeightedGraph", wg)
+		checkGraph(t, "SimpleWeightedDiGraph", wdg)
+
+		for j := 0; j < 3; j++ {
+			u, v := uint32(r.Intn(nv)), uint32(r.Intn(nv))
+			if g.HasEdge(u, v) || dg.HasEdge(u, v) {
+				continue
+			}
+			if err := g.AddEdge(u, v); err != nil {
+				t.Fatal(err)
+			}
+			if err := dg.AddEdge(u, v); err != nil {
+				t.Fatal(err)
+			}
+			if err := wg.AddEdge(u, v, 0.5); err != nil {
+				t.Fatal(err)
+			}
+			if err := wdg.AddEdge(u, v, 0.5); err != nil {
+				t.Fatal(err)
+			}
+		}
+		checkGraph(t, "SimpleGraph after AddEdge", g)
+		checkGraph(t, "SimpleDiGraph after AddEdge", dg)
+		checkGraph(t, "SimpleWeightedGraph after AddEdge", wg)
+		checkGraph(t, "SimpleWeightedDiGraph after AddEdge", wdg)
+
+		fn := filepath.Join(dir, "g")
+		if err := g.Save(fn); err != nil {
+			t.Fatal(err)
+		}
+		lg, err := Load(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGraph(t, "loaded SimpleGraph", lg)
+
+		if err := dg.Save(fn); err != nil {
+			t.Fatal(err)
+		}
+		ldg, err := LoadDiGraph(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGraph(t, "loaded SimpleDiGraph", ldg)
+
+		if err := wg.Save(fn); err != nil {
+			t.Fatal(err)
+		}
+		lwg, err := LoadWeighted(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGraph(t, "loaded SimpleWeightedGraph", lwg)
+
+		if err := wdg.Save(fn); err != nil {
+			t.Fatal(err)
+		}
+		lwdg, err := LoadWeightedDiGraph(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGraph(t, "loaded SimpleWeightedDiGraph", lwdg)
+		if lwdg.NumEdges() != wdg.NumEdges() {
+			t.Errorf("loaded SimpleWeightedDiGraph has %d edges, expected %d", lwdg.NumEdges(), wdg.NumEdges())
+		}
+	}
+}
+
+func TestLoadRejectsOtherKinds(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "g")
+	dg, err := NewDiGraph([]uint32{0, 1}, []uint32{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dg.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(fn); err == nil {
+		t.Error("Load accepted a directed graph")
+	}
+	if _, err := LoadWeightedDiGraph(fn); err == nil {
+		t.Error("LoadWeightedDiGraph accepted an unweighted graph")
+	}
+}
+
+func TestSaveLoadEmptyGraph(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "g")
+	g, err := New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg, err := NewDiGraph(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wg, err := NewWeighted(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wdg, err := NewWeightedDiGraph(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := []Graph{}
+	if err := g.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	lg, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded = append(loaded, lg)
+	if err := dg.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	ldg, err := LoadDiGraph(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded = append(loaded, ldg)
+	if err := wg.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	lwg, err := LoadWeighted(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded = append(loaded, lwg)
+	if err := wdg.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	lwdg, err := LoadWeightedDiGraph(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded = append(loaded, lwdg)
+	for _, l := range loaded {
+		checkGraph(t, "loaded empty graph", l)
+		if l.NumEdges() != 0 {
+			t.Errorf("loaded empty graph %v has %d edges", l, l.NumEdges())
+		}
+	}
+}
+
+func TestLoadShortFile(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "g")
+	for _, n := range []int{1, 7, 8, 20} {
+		if err := os.WriteFile(fn, make([]byte, n), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadDiGraph(fn); err == nil {
+			t.Errorf("LoadDiGraph accepted a file of %d bytes", n)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -8,15 +9,38 @@ import (
 	"github.com/sbromberger/gographs/converter"
 )
 
+var (
+	directed = flag.Bool("d", false, "read the edgelist as a directed graph")
+	weighted = flag.Bool("w", false, "read edge weights from the third column of the edgelist")
+)
+
+type saver interface {
+	Save(filename string) error
+}
+
+func readGraph(in string) (saver, error) {
+	switch {
+	case *directed && *weighted:
+		return converter.ReadWeightedDiEdgeList(in)
+	case *weighted:
+		return converter.ReadWeightedEdgeList(in)
+	case *directed:
+		return converter.ReadDiEdgeList(in)
+	default:
+		return converter.ReadEdgeList(in)
+	}
+}
+
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: ", os.Args[0], " infile outfile")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Println("Usage: ", os.Args[0], " [-d] [-w] infile outfile")
 		os.Exit(1)
 	}
-	in := os.Args[1]
-	out := os.Args[2]
+	in := flag.Arg(0)
+	out := flag.Arg(1)
 
-	g, err := converter.ReadEdgeList(in)
+	g, err := readGraph(in)
 	if err != nil {
 		log.Fatalf("Can't read %s: %v", in, err)
 	}
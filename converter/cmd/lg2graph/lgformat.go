@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -8,19 +9,31 @@ import (
 	"github.com/sbromberger/gographs/converter"
 )
 
+var directed = flag.Bool("d", false, "read the graph as a directed graph")
+
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: ", os.Args[0], " infile outfile")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Println("Usage: ", os.Args[0], " [-d] infile outfile")
 		os.Exit(1)
 	}
-	in := os.Args[1]
-	out := os.Args[2]
+	in := flag.Arg(0)
+	out := flag.Arg(1)
 
-	g, err := converter.GraphFromLG(in)
-	if err != nil {
-		log.Fatalf("Can't read %s: %v", in, err)
+	var err error
+	if *directed {
+		g, rerr := converter.DiGraphFromLG(in)
+		if rerr != nil {
+			log.Fatalf("Can't read %s: %v", in, rerr)
+		}
+		err = g.Save(out)
+	} else {
+		g, rerr := converter.GraphFromLG(in)
+		if rerr != nil {
+			log.Fatalf("Can't read %s: %v", in, rerr)
+		}
+		err = g.Save(out)
 	}
-	err = g.Save(out)
 	if err != nil {
 		log.Fatalf("Can't write %s: %v", out, err)
 	}
@@ -16,10 +16,16 @@ func splitLine(line string) []string {
 	return s
 }
 
-func readEdgeList(scanner *bufio.Scanner, offset uint32) ([]uint32, []uint32, error) {
+// readEdgeList returns vectors of source and dest vertices from lines of `columns` columns, and a
+// vector of weights from the third column if `columns` is 3.
+func readEdgeList(scanner *bufio.Scanner, offset uint32, columns int) ([]uint32, []uint32, []float32, error) {
 	var l string
 	ss := make([]uint32, 0, 100)
 	ds := make([]uint32, 0, 100)
+	var ws []float32
+	if columns == 3 {
+		ws = make([]float32, 0, 100)
+	}
 	for scanner.Scan() {
 		l = scanner.Text()
 
@@ -27,16 +33,23 @@ func readEdgeList(scanner *bufio.Scanner, offset uint32) ([]uint32, []uint32, er
 			continue
 		}
 		pieces := splitLine(l)
-		if len(pieces) != 2 {
-			return []uint32{}, []uint32{}, fmt.Errorf("Parsing error: got %s", l)
+		if len(pieces) != columns {
+			return []uint32{}, []uint32{}, []float32{}, fmt.Errorf("Parsing error: got %s", l)
 		}
 		u64, err := strconv.ParseUint(pieces[0], 10, 32)
 		if err != nil {
-			return []uint32{}, []uint32{}, fmt.Errorf("Parsing error: got %s", l)
+			return []uint32{}, []uint32{}, []float32{}, fmt.Errorf("Parsing error: got %s", l)
 		}
 		v64, err := strconv.ParseUint(pieces[1], 10, 32)
 		if err != nil {
-			return []uint32{}, []uint32{}, fmt.Errorf("Parsing error: got %s", l)
+			return []uint32{}, []uint32{}, []float32{}, fmt.Errorf("Parsing error: got %s", l)
+		}
+		if columns == 3 {
+			w64, err := strconv.ParseFloat(pieces[2], 32)
+			if err != nil {
+				return []uint32{}, []uint32{}, []float32{}, fmt.Errorf("Parsing error: got %s", l)
+			}
+			ws = append(ws, float32(w64))
 		}
 		u := uint32(u64) - offset
 		v := uint32(v64) - offset
@@ -44,24 +57,56 @@ func readEdgeList(scanner *bufio.Scanner, offset uint32) ([]uint32, []uint32, er
 		ds = append(ds, v)
 	}
 	if err := scanner.Err(); err != nil {
-		return []uint32{}, []uint32{}, fmt.Errorf("Other error: %v", err)
+		return []uint32{}, []uint32{}, []float32{}, fmt.Errorf("Other error: %v", err)
 	}
-	return ss, ds, nil
+	return ss, ds, ws, nil
 }
 
-// ReadEdgeList returns a graph from an edgelist.
-func ReadEdgeList(fn string) (graph.SimpleGraph, error) {
+// readEdgeListFile returns vectors of source vertices, dest vertices and, if `columns` is 3,
+// weights from an edgelist file.
+func readEdgeListFile(fn string, columns int) ([]uint32, []uint32, []float32, error) {
 	f, err := os.OpenFile(fn, os.O_RDONLY, 0644)
 	if err != nil {
-		return graph.SimpleGraph{}, err
+		return []uint32{}, []uint32{}, []float32{}, err
 	}
 	defer f.Close()
 
 	scanner := bufio.NewScanner(f)
-	ss, ds, err := readEdgeList(scanner, 0)
+	return readEdgeList(scanner, 0, columns)
+}
+
+// ReadEdgeList returns an undirected graph from an edgelist.
+func ReadEdgeList(fn string) (graph.SimpleGraph, error) {
+	ss, ds, _, err := readEdgeListFile(fn, 2)
 	if err != nil {
 		return graph.SimpleGraph{}, err
 	}
-
 	return graph.New(ss, ds)
 }
+
+// ReadDiEdgeList returns a directed graph from an edgelist.
+func ReadDiEdgeList(fn string) (graph.SimpleDiGraph, error) {
+	ss, ds, _, err := readEdgeListFile(fn, 2)
+	if err != nil {
+		return graph.SimpleDiGraph{}, err
+	}
+	return graph.NewDiGraph(ss, ds)
+}
+
+// ReadWeightedEdgeList returns an undirected weighted graph from an edgelist with a weight in the third column.
+func ReadWeightedEdgeList(fn string) (graph.SimpleWeightedGraph, error) {
+	ss, ds, ws, err := readEdgeListFile(fn, 3)
+	if err != nil {
+		return graph.SimpleWeightedGraph{}, err
+	}
+	return graph.NewWeighted(ss, ds, ws)
+}
+
+// ReadWeightedDiEdgeList returns a directed weighted graph from an edgelist with a weight in the third column.
+func ReadWeightedDiEdgeList(fn string) (graph.SimpleWeightedDiGraph, error) {
+	ss, ds, ws, err := readEdgeListFile(fn, 3)
+	if err != nil {
+		return graph.SimpleWeightedDiGraph{}, err
+	}
+	return graph.NewWeightedDiGraph(ss, ds, ws)
+}
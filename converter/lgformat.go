@@ -8,20 +8,35 @@ import (
 	graph "github.com/sbromberger/gographs"
 )
 
-// GraphFromLG reads a graph from lightgraphs format. Ignores header.
-func GraphFromLG(fn string) (graph.SimpleGraph, error) {
+// readLG returns vectors of source and dest vertices from a lightgraphs format file. Ignores header.
+func readLG(fn string) ([]uint32, []uint32, error) {
 	f, err := os.OpenFile(fn, os.O_RDONLY, 0644)
 	if err != nil {
-		return graph.SimpleGraph{}, fmt.Errorf("Open failed: %v", err)
+		return []uint32{}, []uint32{}, fmt.Errorf("Open failed: %v", err)
 	}
 	defer f.Close()
 
 	scanner := bufio.NewScanner(f)
 	scanner.Scan() // read header
 
-	ss, ds, err := readEdgeList(scanner, 1) // offset is 1 because Julia is 1-indexed
+	ss, ds, _, err := readEdgeList(scanner, 1, 2) // offset is 1 because Julia is 1-indexed
+	return ss, ds, err
+}
+
+// GraphFromLG reads an undirected graph from lightgraphs format. Ignores header.
+func GraphFromLG(fn string) (graph.SimpleGraph, error) {
+	ss, ds, err := readLG(fn)
 	if err != nil {
 		return graph.SimpleGraph{}, err
 	}
 	return graph.New(ss, ds)
 }
+
+// DiGraphFromLG reads a directed graph from lightgraphs format. Ignores header.
+func DiGraphFromLG(fn string) (graph.SimpleDiGraph, error) {
+	ss, ds, err := readLG(fn)
+	if err != nil {
+		return graph.SimpleDiGraph{}, err
+	}
+	return graph.NewDiGraph(ss, ds)
+}
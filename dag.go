@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CycleError is returned by algorithms that require a directed acyclic graph when the graph has
+// a cycle.
+type CycleError struct {
+	// Cycle holds the vertices of one cycle in order; the last vertex has an edge to the first.
+	Cycle []uint32
+}
+
+func (e CycleError) Error() string {
+	return fmt.Sprintf("graph has a cycle: %v", e.Cycle)
+}
+
+// backEdgeVisitor records the first back edge examined by a depth-first search.
+type backEdgeVisitor struct {
+	NullDFSVisitor
+	u, v  uint32
+	found bool
+}
+
+func (vis *backEdgeVisitor) ExamineEdge(u, v uint32, kind EdgeKind) {
+	if kind == BackEdge && !vis.found {
+		vis.u, vis.v, vis.found = u, v, true
+	}
+}
+
+// IsCyclic returns true and the vertices of one cycle in order, the last vertex having an edge
+// to the first, if g has a cycle, or false and nil otherwise. A cycle of an undirected graph is
+// a self loop or has at least three vertices; a forest has none.
+func IsCyclic(g Graph) (bool, []uint32) {
+	vis := &backEdgeVisitor{}
+	state := DFSForest(g, vis)
+	if !vis.found {
+		return false, nil
+	}
+	// the back edge leads from u to its ancestor v; the tree path from v to u closes the cycle.
+	cycle := []uint32{}
+	for w := vis.u; w != vis.v; w = state.Parents[w] {
+		cycle = append(cycle, w)
+	}
+	cycle = append(cycle, vis.v)
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return true, cycle
+}
+
+// cycleError returns a CycleError with a cycle of g, which must have one.
+func cycleError(g Graph) error {
+	_, cycle := IsCyclic(g)
+	return CycleError{Cycle: cycle}
+}
+
+// TopologicalSort returns the vertices of g in an order in which every edge leads from an
+// earlier vertex to a later one, using Kahn's algorithm. Vertices with no remaining in edges are
+// taken in increasing order of discovery, starting with the vertices with an InDegree of 0 in
+// increasing order. It returns a CycleError if g has a cycle, and an error if g is undirected.
+func TopologicalSort(g Graph) ([]uint32, error) {
+	nv := g.NumVertices()
+	if !g.IsDirected() {
+		return nil, errors.New("an undirected graph has no topological order")
+	}
+	indegree := make([]uint32, nv)
+	order := make([]uint32, 0, nv)
+	for v := u0; v < nv; v++ {
+		indegree[v] = g.InDegree(v)
+		if indegree[v] == 0 {
+			order = append(order, v)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, v := range g.OutNeighbors(order[i]) {
+			indegree[v]--
+			if indegree[v] == 0 {
+				order = append(order, v)
+			}
+		}
+	}
+	if len(order) < int(nv) {
+		return nil, cycleError(g)
+	}
+	return order, nil
+}
+
+// DAGLongestPath returns a path of greatest total weight in the directed acyclic graph g, which may be
+// a single vertex, and its weight, or a CycleError if g has a cycle.
+func DAGLongestPath(g Graph, weightFn func(uint32, uint32) float32) ([]uint32, float32, error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(order) == 0 {
+		return []uint32{}, 0, nil
+	}
+	w := newWeigher(g, weightFn)
+	nv := g.NumVertices()
+	dists := make([]float32, nv) // the weight of the heaviest path ending at each vertex
+	parents := make([]uint32, nv)
+	for i := range parents {
+		parents[i] = NoParent
+	}
+	end := order[0]
+	for _, u := range order {
+		vs, ws := w.outEdges(g, u)
+		for i, v := range vs {
+			if alt := dists[u] + w.weight(u, v, ws, i); alt > dists[v] {
+				dists[v] = alt
+				parents[v] = u
+			}
+		}
+		if dists[u] > dists[end] {
+			end = u
+		}
+	}
+
+	path := []uint32{}
+	for v := end; v != NoParent; v = parents[v] {
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dists[end], nil
+}
+
+// TransitiveReduction returns the transitive reduction of the directed acyclic graph g, the
+// graph with the fewest edges that has the same reachability as g: the edges of g from u to v
+// for which there is no other path from u to v. It takes time proportional to NumVertices times
+// NumEdges in the worst case. It returns a CycleError if g has a cycle.
+func TransitiveReduction(g Graph) (SimpleDiGraph, error) {
+	if _, err := TopologicalSort(g); err != nil {
+		return SimpleDiGraph{}, err
+	}
+	nv := g.NumVertices()
+	// mark[w] == u+1 if w is reachable from u by a path of two or more edges.
+	mark := make([]uint32, nv)
+	stack := []uint32{}
+	ss, ds := []uint32{}, []uint32{}
+	for u := u0; u < nv; u++ {
+		for _, v := range g.OutNeighbors(u) {
+			for _, w := range g.OutNeighbors(v) {
+				if mark[w] != u+1 {
+					mark[w] = u + 1
+					stack = append(stack, w)
+				}
+			}
+			for len(stack) > 0 {
+				x := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, w := range g.OutNeighbors(x) {
+					if mark[w] != u+1 {
+						mark[w] = u + 1
+						stack = append(stack, w)
+					}
+				}
+			}
+		}
+		for _, v := range g.OutNeighbors(u) {
+			if mark[v] != u+1 {
+				ss = append(ss, u)
+				ds = append(ds, v)
+			}
+		}
+	}
+	return newDiGraph(nv, ss, ds)
+}
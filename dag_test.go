@@ -0,0 +1,229 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkCycle fails unless cycle is a cycle of g: distinct vertices, each with an edge to the next
+// and the last to the first, and at least three of them if g is undirected and cycle is no self loop.
+func checkCycle(t *testing.T, g Graph, cycle []uint32) {
+	t.Helper()
+	seen := map[uint32]bool{}
+	for i, u := range cycle {
+		if seen[u] || !g.HasEdge(u, cycle[(i+1)%len(cycle)]) {
+			t.Fatalf("%v: %v is not a cycle", g, cycle)
+		}
+		seen[u] = true
+	}
+	if len(cycle) == 0 || (!g.IsDirected() && len(cycle) == 2) {
+		t.Fatalf("%v: %v is not a cycle", g, cycle)
+	}
+}
+
+// randomDAG returns a random directed acyclic graph of nv vertices whose edges lead forward in a
+// random order of the vertices.
+func randomDAG(t *testing.T, r *rand.Rand, nv, ne int) SimpleDiGraph {
+	perm := r.Perm(nv)
+	ss, ds := []uint32{}, []uint32{}
+	for i := 0; i < ne; i++ {
+		u, v := r.Intn(nv), r.Intn(nv)
+		if u == v {
+			continue
+		}
+		if u > v {
+			u, v = v, u
+		}
+		ss, ds = append(ss, uint32(perm[u])), append(ds, uint32(perm[v]))
+	}
+	g, err := newDiGraph(uint32(nv), ss, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestIsCyclic(t *testing.T) {
+	tests := []struct {
+		name   string
+		g      func() (Graph, error)
+		cyclic bool
+	}{
+		{"empty", func() (Graph, error) { return NewDiGraph(nil, nil) }, false},
+		{"directed path", func() (Graph, error) { return NewDiGraph([]uint32{0, 1}, []uint32{1, 2}) }, false},
+		{"directed diamond", func() (Graph, error) { return NewDiGraph([]uint32{0, 0, 1, 2}, []uint32{1, 2, 3, 3}) }, false},
+		{"directed cycle", func() (Graph, error) { return NewDiGraph([]uint32{0, 1, 2, 2}, []uint32{1, 2, 0, 3}) }, true},
+		{"directed self loop", func() (Graph, error) { return NewDiGraph([]uint32{0, 1}, []uint32{1, 1}) }, true},
+		{"undirected path", func() (Graph, error) { return New([]uint32{0, 1}, []uint32{1, 2}) }, false},
+		{"undirected forest", func() (Graph, error) { return New([]uint32{0, 0, 3, 4}, []uint32{1, 2, 4, 5}) }, false},
+		{"undirected triangle", func() (Graph, error) { return New([]uint32{0, 1, 2, 2}, []uint32{1, 2, 0, 3}) }, true},
+		{"undirected self loop", func() (Graph, error) { return New([]uint32{0, 1}, []uint32{1, 1}) }, true},
+	}
+	for _, tt := range tests {
+		g, err := tt.g()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cyclic, cycle := IsCyclic(g)
+		if cyclic != tt.cyclic {
+			t.Errorf("%s: IsCyclic = %v %v, expected %v", tt.name, cyclic, cycle, tt.cyclic)
+			continue
+		}
+		if cyclic {
+			checkCycle(t, g, cycle)
+		} else if cycle != nil {
+			t.Errorf("%s: IsCyclic returned cycle %v for an acyclic graph", tt.name, cycle)
+		}
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g, err := NewDiGraph([]uint32{5, 5, 4, 4, 2, 3}, []uint32{2, 0, 0, 1, 3, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := TopologicalSort(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint32{4, 5, 0, 2, 3, 1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("TopologicalSort = %v, expected %v", order, want)
+	}
+
+	empty, _ := NewDiGraph(nil, nil)
+	if order, err := TopologicalSort(empty); err != nil || len(order) != 0 {
+		t.Errorf("TopologicalSort of an empty graph = %v, %v", order, err)
+	}
+
+	cyclic, _ := NewDiGraph([]uint32{0, 1, 2, 3}, []uint32{1, 2, 3, 1})
+	_, err = TopologicalSort(cyclic)
+	var ce CycleError
+	if !errors.As(err, &ce) {
+		t.Fatalf("TopologicalSort of a cyclic graph returned %v, expected a CycleError", err)
+	}
+	checkCycle(t, cyclic, ce.Cycle)
+
+	path, _ := New([]uint32{0, 1}, []uint32{1, 2})
+	if _, err := TopologicalSort(path); err == nil || errors.As(err, &ce) {
+		t.Errorf("TopologicalSort of an undirected graph returned %v, expected an error other than CycleError", err)
+	}
+
+	r := rand.New(rand.NewSource(24))
+	for i := 0; i < 50; i++ {
+		nv := 1 + r.Intn(40)
+		g := randomDAG(t, r, nv, r.Intn(3*nv))
+		order, err := TopologicalSort(g)
+		if err != nil {
+			t.Fatalf("%v: %v", g, err)
+		}
+		pos := make([]int, nv)
+		for i, u := range order {
+			pos[u] = i
+		}
+		for u := u0; u < g.NumVertices(); u++ {
+			for _, v := range g.OutNeighbors(u) {
+				if pos[u] >= pos[v] {
+					t.Fatalf("%v: %d -> %d goes backward in %v", g, u, v, order)
+				}
+			}
+		}
+	}
+}
+
+func TestDAGLongestPath(t *testing.T) {
+	g, err := NewWeightedDiGraph([]uint32{0, 1, 0, 2, 3}, []uint32{1, 2, 2, 3, 4}, []float32{3, 4, 5, -1, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, weight, err := DAGLongestPath(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint32{0, 1, 2, 3, 4}; !reflect.DeepEqual(path, want) || weight != 16 {
+		t.Errorf("DAGLongestPath = %v, %v, expected %v, 16", path, weight, want)
+	}
+	path, weight, _ = DAGLongestPath(g, func(u, v uint32) float32 { return 1 })
+	if len(path) != 5 || weight != 4 {
+		t.Errorf("DAGLongestPath with unit weights = %v, %v, expected a path of 4 edges", path, weight)
+	}
+
+	negative, _ := NewWeightedDiGraph([]uint32{0, 1}, []uint32{1, 2}, []float32{-1, -2})
+	if path, weight, _ := DAGLongestPath(negative, nil); len(path) != 1 || weight != 0 {
+		t.Errorf("DAGLongestPath with negative weights = %v, %v, expected a single vertex and 0", path, weight)
+	}
+
+	empty, _ := NewDiGraph(nil, nil)
+	if path, weight, err := DAGLongestPath(empty, nil); err != nil || len(path) != 0 || weight != 0 {
+		t.Errorf("DAGLongestPath of an empty graph = %v, %v, %v", path, weight, err)
+	}
+
+	cyclic, _ := NewDiGraph([]uint32{0, 1}, []uint32{1, 0})
+	if _, _, err := DAGLongestPath(cyclic, nil); !errors.As(err, &CycleError{}) {
+		t.Errorf("DAGLongestPath of a cyclic graph returned %v, expected a CycleError", err)
+	}
+}
+
+// reachableFrom returns the vertices reachable from src by a path of one or more edges.
+func reachableFrom(g Graph, src uint32) map[uint32]bool {
+	seen := map[uint32]bool{}
+	stack := []uint32{src}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range g.OutNeighbors(u) {
+			if !seen[v] {
+				seen[v] = true
+				stack = append(stack, v)
+			}
+		}
+	}
+	return seen
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g, err := NewDiGraph([]uint32{0, 1, 0, 2, 0}, []uint32{1, 2, 2, 3, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := TransitiveReduction(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.NumEdges() != 3 || !tr.HasEdge(0, 1) || !tr.HasEdge(1, 2) || !tr.HasEdge(2, 3) {
+		t.Errorf("TransitiveReduction = %v, expected 0 -> 1 -> 2 -> 3", tr)
+	}
+
+	empty, _ := NewDiGraph(nil, nil)
+	if tr, err := TransitiveReduction(empty); err != nil || tr.NumVertices() != 0 {
+		t.Errorf("TransitiveReduction of an empty graph = %v, %v", tr, err)
+	}
+	cyclic, _ := NewDiGraph([]uint32{0, 1}, []uint32{1, 0})
+	if _, err := TransitiveReduction(cyclic); !errors.As(err, &CycleError{}) {
+		t.Errorf("TransitiveReduction of a cyclic graph returned %v, expected a CycleError", err)
+	}
+
+	r := rand.New(rand.NewSource(25))
+	for i := 0; i < 50; i++ {
+		nv := 1 + r.Intn(30)
+		g := randomDAG(t, r, nv, r.Intn(4*nv))
+		tr, err := TransitiveReduction(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for u := u0; u < g.NumVertices(); u++ {
+			if !reflect.DeepEqual(reachableFrom(tr, u), reachableFrom(g, u)) {
+				t.Fatalf("%v: the reduction %v reaches different vertices from %d", g, tr, u)
+			}
+			for _, v := range tr.OutNeighbors(u) {
+				// no edge of the reduction is implied by a longer path.
+				for _, x := range tr.OutNeighbors(u) {
+					if x != v && reachableFrom(tr, x)[v] {
+						t.Fatalf("%v: the reduction %v keeps %d -> %d, also reached through %d", g, tr, u, v, x)
+					}
+				}
+			}
+		}
+	}
+}
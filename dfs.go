@@ -0,0 +1,161 @@
+package graph
+
+// EdgeKind classifies an edge examined by a depth-first search.
+type EdgeKind uint8
+
+const (
+	// TreeEdge leads to a newly discovered vertex.
+	TreeEdge EdgeKind = iota
+	// BackEdge leads to an ancestor of the current vertex that has not been finished.
+	BackEdge
+	// ForwardEdge leads to a finished descendant of the current vertex.
+	ForwardEdge
+	// CrossEdge leads to a finished vertex that is not a descendant of the current vertex.
+	CrossEdge
+)
+
+func (k EdgeKind) String() string {
+	switch k {
+	case TreeEdge:
+		return "tree"
+	case BackEdge:
+		return "back"
+	case ForwardEdge:
+		return "forward"
+	case CrossEdge:
+		return "cross"
+	}
+	return "unknown"
+}
+
+// DFSVisitor receives events from a depth-first search.
+type DFSVisitor interface {
+	// DiscoverVertex is called when u is first visited.
+	DiscoverVertex(u uint32)
+	// ExamineEdge is called for each edge from u to v, classified by kind. In an undirected
+	// graph, each edge is examined once, as a tree edge or a back edge, and the edge to the
+	// parent of u is not examined again.
+	ExamineEdge(u, v uint32, kind EdgeKind)
+	// FinishVertex is called when all of the out neighbors of u have been examined.
+	FinishVertex(u uint32)
+}
+
+// NullDFSVisitor is a DFSVisitor that ignores all events. Embed it in a type to
+// implement only some events.
+type NullDFSVisitor struct{}
+
+func (NullDFSVisitor) DiscoverVertex(u uint32)                {}
+func (NullDFSVisitor) ExamineEdge(u, v uint32, kind EdgeKind) {}
+func (NullDFSVisitor) FinishVertex(u uint32)                  {}
+
+// DFSState holds the result of a depth-first search. Vertices that were not visited
+// have a Discover and Finish of math.MaxUint32 and a parent of NoParent.
+type DFSState struct {
+	Parents  []uint32 // the parent of each vertex in the DFS forest; roots have NoParent
+	Discover []uint32 // the order in which each vertex was discovered, starting at 0
+	Finish   []uint32 // the order in which each vertex was finished, starting at 0
+}
+
+// dfsFrame is a vertex on the DFS stack and the index of its next out neighbor to examine.
+type dfsFrame struct {
+	u    uint32
+	next int
+}
+
+// dfsSearch holds the state of a depth-first search across one or more roots.
+type dfsSearch struct {
+	g                        Graph
+	vis                      DFSVisitor
+	directed                 bool
+	state                    DFSState
+	stack                    []dfsFrame
+	discoverTime, finishTime uint32
+}
+
+func newDFSSearch(g Graph, vis DFSVisitor) *dfsSearch {
+	if vis == nil {
+		vis = NullDFSVisitor{}
+	}
+	nv := g.NumVertices()
+	s := &dfsSearch{
+		g:        g,
+		vis:      vis,
+		directed: g.IsDirected(),
+		state: DFSState{
+			Parents:  make([]uint32, nv),
+			Discover: make([]uint32, nv),
+			Finish:   make([]uint32, nv),
+		},
+	}
+	for i := u0; i < nv; i++ {
+		s.state.Parents[i] = NoParent
+		s.state.Discover[i] = unvisited
+		s.state.Finish[i] = unvisited
+	}
+	return s
+}
+
+func (s *dfsSearch) discover(u, parent uint32) {
+	s.state.Parents[u] = parent
+	s.state.Discover[u] = s.discoverTime
+	s.discoverTime++
+	s.vis.DiscoverVertex(u)
+	s.stack = append(s.stack, dfsFrame{u: u})
+}
+
+// visit searches from root, which must not have been discovered, without recursion.
+func (s *dfsSearch) visit(root uint32) {
+	disc, fin, parents := s.state.Discover, s.state.Finish, s.state.Parents
+	s.discover(root, NoParent)
+	for len(s.stack) > 0 {
+		top := &s.stack[len(s.stack)-1]
+		u := top.u
+		neighbors := s.g.OutNeighbors(u)
+		if top.next >= len(neighbors) {
+			s.stack = s.stack[:len(s.stack)-1]
+			fin[u] = s.finishTime
+			s.finishTime++
+			s.vis.FinishVertex(u)
+			continue
+		}
+		v := neighbors[top.next]
+		top.next++
+		switch {
+		case disc[v] == unvisited:
+			s.vis.ExamineEdge(u, v, TreeEdge)
+			s.discover(v, u)
+		case !s.directed:
+			// each undirected edge is examined from its lower end in the DFS tree only.
+			if fin[v] == unvisited && v != parents[u] {
+				s.vis.ExamineEdge(u, v, BackEdge)
+			}
+		case fin[v] == unvisited:
+			s.vis.ExamineEdge(u, v, BackEdge)
+		case disc[u] < disc[v]:
+			s.vis.ExamineEdge(u, v, ForwardEdge)
+		default:
+			s.vis.ExamineEdge(u, v, CrossEdge)
+		}
+	}
+}
+
+// DFS performs an iterative depth-first search from src, reporting events to vis, and
+// returns a DFSState for the vertices reachable from src. vis may be nil.
+func DFS(g Graph, src uint32, vis DFSVisitor) DFSState {
+	s := newDFSSearch(g, vis)
+	s.visit(src)
+	return s.state
+}
+
+// DFSForest performs iterative depth-first searches from each undiscovered vertex in
+// increasing order until all vertices are visited, reporting events to vis, and returns
+// a DFSState for the resulting forest. vis may be nil.
+func DFSForest(g Graph, vis DFSVisitor) DFSState {
+	s := newDFSSearch(g, vis)
+	for u := u0; u < g.NumVertices(); u++ {
+		if s.state.Discover[u] == unvisited {
+			s.visit(u)
+		}
+	}
+	return s.state
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// eventVisitor records the events of a depth-first search.
+type eventVisitor struct {
+	events []string
+}
+
+func (vis *eventVisitor) DiscoverVertex(u uint32) {
+	vis.events = append(vis.events, fmt.Sprintf("discover %d", u))
+}
+
+func (vis *eventVisitor) ExamineEdge(u, v uint32, kind EdgeKind) {
+	vis.events = append(vis.events, fmt.Sprintf("%v %d %d", kind, u, v))
+}
+
+func (vis *eventVisitor) FinishVertex(u uint32) {
+	vis.events = append(vis.events, fmt.Sprintf("finish %d", u))
+}
+
+func TestDFSForestDirected(t *testing.T) {
+	g, err := NewDiGraph([]uint32{0, 0, 1, 2, 3}, []uint32{1, 2, 2, 0, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vis := &eventVisitor{}
+	state := DFSForest(g, vis)
+	want := []string{
+		"discover 0", "tree 0 1", "discover 1", "tree 1 2", "discover 2", "back 2 0", "finish 2",
+		"finish 1", "forward 0 2", "finish 0", "discover 3", "cross 3 2", "finish 3",
+	}
+	if !reflect.DeepEqual(vis.events, want) {
+		t.Errorf("DFSForest events = %v, expected %v", vis.events, want)
+	}
+	wantState := DFSState{
+		Parents:  []uint32{NoParent, 0, 1, NoParent},
+		Discover: []uint32{0, 1, 2, 3},
+		Finish:   []uint32{2, 1, 0, 3},
+	}
+	if !reflect.DeepEqual(state, wantState) {
+		t.Errorf("DFSForest = %+v, expected %+v", state, wantState)
+	}
+
+	state = DFS(g, 0, nil)
+	if state.Discover[3] != unvisited || state.Finish[3] != unvisited || state.Parents[3] != NoParent {
+		t.Errorf("DFS from 0 visited 3: %+v", state)
+	}
+}
+
+func TestDFSUndirected(t *testing.T) {
+	// a triangle 0-1-2 with a tail 2-3; each edge is examined once.
+	g, err := New([]uint32{0, 1, 2, 2}, []uint32{1, 2, 0, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vis := &eventVisitor{}
+	DFS(g, 0, vis)
+	want := []string{
+		"discover 0", "tree 0 1", "discover 1", "tree 1 2", "discover 2", "back 2 0", "tree 2 3",
+		"discover 3", "finish 3", "finish 2", "finish 1", "finish 0",
+	}
+	if !reflect.DeepEqual(vis.events, want) {
+		t.Errorf("DFS events = %v, expected %v", vis.events, want)
+	}
+}
+
+func TestDFSLongPath(t *testing.T) {
+	// a path deeper than a recursive search could go.
+	n := uint32(1 << 20)
+	ss, ds := make([]uint32, n-1), make([]uint32, n-1)
+	for i := range ss {
+		ss[i], ds[i] = uint32(i), uint32(i+1)
+	}
+	g, err := NewDiGraph(ss, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := DFS(g, 0, nil)
+	if state.Discover[n-1] != n-1 || state.Finish[n-1] != 0 || state.Parents[n-1] != n-2 {
+		t.Errorf("DFS reached %d at %d, finished at %d with parent %d", n-1, state.Discover[n-1], state.Finish[n-1], state.Parents[n-1])
+	}
+}
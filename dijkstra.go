@@ -1,12 +1,15 @@
 package graph
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/shawnsmithdev/zermelo/zuint32"
 )
 
-// DijkstraState is a state holding Dijkstra Shortest Path info.
+// DijkstraState is a state holding Dijkstra Shortest Path info. The parent of the
+// source and of unreachable vertices is NoParent, and unreachable vertices have no
+// predecessors.
 type DijkstraState struct {
 	Parents      []uint32
 	Dists        []float32
@@ -23,6 +26,108 @@ func (d DijkstraState) String() string {
 	return s
 }
 
+// HasPathTo returns true if v is reachable from the source.
+func (d DijkstraState) HasPathTo(v uint32) bool {
+	return d.Dists[v] < maxDist
+}
+
+// PathTo returns a shortest path from the source to v, following Parents, as a vector of
+// vertices starting with the source and ending with v. It returns nil if v is not reachable.
+func (d DijkstraState) PathTo(v uint32) []uint32 {
+	if !d.HasPathTo(v) {
+		return nil
+	}
+	path := []uint32{}
+	for ; v != NoParent; v = d.Parents[v] {
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// AllPathsTo returns an iterator over all shortest paths from the source to v, following
+// Predecessors. The number of shortest paths may grow exponentially, so paths are generated
+// as they are requested. It returns an error if the DijkstraState has no predecessors.
+func (d DijkstraState) AllPathsTo(v uint32) (*PathIter, error) {
+	if len(d.Predecessors) == 0 {
+		return nil, errors.New("DijkstraState was computed without predecessors")
+	}
+	it := &PathIter{preds: d.Predecessors, onPath: make(map[uint32]struct{})}
+	if !d.HasPathTo(v) {
+		it.done = true
+		return it, nil
+	}
+	it.push(v)
+	it.advance()
+	return it, nil
+}
+
+// pathFrame is a vertex on a path being built by a PathIter and the index of its next predecessor.
+type pathFrame struct {
+	v    uint32
+	next int
+}
+
+// PathIter iterates over the shortest paths to a vertex.
+type PathIter struct {
+	preds  [][]uint32
+	stack  []pathFrame
+	onPath map[uint32]struct{}
+	path   []uint32
+	done   bool
+}
+
+func (it *PathIter) push(v uint32) {
+	it.stack = append(it.stack, pathFrame{v: v})
+	it.onPath[v] = struct{}{}
+}
+
+func (it *PathIter) pop() {
+	delete(it.onPath, it.stack[len(it.stack)-1].v)
+	it.stack = it.stack[:len(it.stack)-1]
+}
+
+// advance walks predecessors depth-first from the target until it reaches the source, which has
+// no predecessors, and records the path.
+func (it *PathIter) advance() {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		preds := it.preds[top.v]
+		if len(preds) == 0 {
+			it.path = make([]uint32, len(it.stack))
+			for i, f := range it.stack {
+				it.path[len(it.stack)-1-i] = f.v
+			}
+			it.pop()
+			return
+		}
+		if top.next >= len(preds) {
+			it.pop()
+			continue
+		}
+		u := preds[top.next]
+		top.next++
+		if _, ok := it.onPath[u]; !ok { // skip zero-weight cycles
+			it.push(u)
+		}
+	}
+	it.done = true
+}
+
+// Next returns the next shortest path, as a vector of vertices starting with the source.
+func (it *PathIter) Next() []uint32 {
+	path := it.path
+	it.advance()
+	return path
+}
+
+// Done returns true if all shortest paths have been returned.
+func (it *PathIter) Done() bool {
+	return it.done
+}
+
 func min(a, b float32) float32 {
 	if a < b {
 		return a
@@ -31,8 +136,141 @@ func min(a, b float32) float32 {
 }
 
 // Dijkstra performs a Dijkstra Shortest Paths calculation from vertex `src` and returns a DijkstraState.
-// If `withpreds` is true, track all predecessors.
+// If `weightFn` is nil, the weights stored in a WeightedGraph are used, or 1 for other graphs; the
+// other functions taking a `weightFn` do the same. Weights must be non-negative.
+// If `withpreds` is true, track all predecessors. The parent of a vertex is its lowest-numbered predecessor.
 func Dijkstra(g Graph, src uint32, weightFn func(uint32, uint32) float32, withPreds bool) DijkstraState {
+	w := newWeigher(g, weightFn)
+	nv := g.NumVertices()
+	dists := make([]float32, nv)
+	for i := range dists {
+		dists[i] = maxDist
+	}
+
+	dists[src] = 0
+	h := distHeap{}
+	h.Push(src, 0)
+	for h.Len() > 0 {
+		u, d := h.Pop()
+		if d > dists[u] { // stale entry
+			continue
+		}
+		vs, ws := w.outEdges(g, u)
+		for i, v := range vs {
+			alt := min(maxDist, d+w.weight(u, v, ws, i))
+			if alt < dists[v] {
+				dists[v] = alt
+				h.Push(v, alt)
+			}
+		}
+	}
+
+	parents, pathcounts, preds := shortestPathTree(g, w, src, dists, withPreds, 1)
+	ds := DijkstraState{
+		Parents:      parents,
+		Dists:        dists,
+		Pathcounts:   pathcounts,
+		Predecessors: preds,
+	}
+	return ds
+}
+
+// isTight returns true if the edge from u to v with weight wt lies on a shortest path.
+func isTight(u, v uint32, wt float32, dists []float32) bool {
+	return u != v && dists[v] < maxDist && min(maxDist, dists[u]+wt) == dists[v]
+}
+
+// releaseTight returns `order` holding the vertices reachable from src over tight edges, starting
+// with src, each after the vertices with tight edges to it, and calls fn(u, v) for each tight edge
+// from u to a later vertex v. npreds[v] must hold the number of tight edges into v and is used up,
+// and released must be false for every vertex. Zero-weight cycles admit no such order, so when no
+// vertex is ready, the first vertex reached from a released one is released early and the tight
+// edges into it from later vertices are skipped. `order` and `reached` are scratch space.
+func releaseTight(g Graph, w weigher, src uint32, dists []float32, npreds []uint32, released []bool, order, reached []uint32, fn func(u, v uint32)) ([]uint32, []uint32) {
+	released[src] = true
+	order = append(order[:0], src)
+	reached = reached[:0]
+	next := 0
+	for i := 0; ; i++ {
+		if i == len(order) {
+			for next < len(reached) && released[reached[next]] {
+				next++
+			}
+			if next == len(reached) {
+				return order, reached
+			}
+			released[reached[next]] = true
+			order = append(order, reached[next])
+		}
+		u := order[i]
+		vs, ws := w.outEdges(g, u)
+		for j, v := range vs {
+			if released[v] || !isTight(u, v, w.weight(u, v, ws, j), dists) {
+				continue
+			}
+			fn(u, v)
+			npreds[v]--
+			if npreds[v] == 0 {
+				released[v] = true
+				order = append(order, v)
+			} else {
+				reached = append(reached, v)
+			}
+		}
+	}
+}
+
+// shortestPathTree computes parents, path counts and, if `withPreds` is true, predecessors
+// from the shortest path distances `dists` from `src`. The predecessors of v are the vertices u
+// for which dists[u] + weight(u, v) == dists[v]. Path counts and parents are taken over those
+// edges in the order of releaseTight, so the parent of v is its lowest-numbered predecessor
+// unless v lies on a zero-weight cycle. `procs` goroutines scan the in edges of the graph.
+func shortestPathTree(g Graph, w weigher, src uint32, dists []float32, withPreds bool, procs int) (parents, pathcounts []uint32, preds [][]uint32) {
+	nv := g.NumVertices()
+	parents = make([]uint32, nv)
+	pathcounts = make([]uint32, nv)
+	npreds := make([]uint32, nv)
+	for i := range parents {
+		parents[i] = NoParent
+	}
+	preds = make([][]uint32, 0)
+	if withPreds {
+		preds = make([][]uint32, nv)
+	}
+
+	blockIter(int(nv), procs, func(low, high int) {
+		for v := uint32(low); v < uint32(high); v++ {
+			if v == src || dists[v] >= maxDist {
+				continue
+			}
+			us, ws := w.inEdges(g, v)
+			for i, u := range us {
+				if !isTight(u, v, w.weight(u, v, ws, i), dists) {
+					continue
+				}
+				npreds[v]++
+				if withPreds {
+					preds[v] = append(preds[v], u)
+				}
+			}
+		}
+	})
+
+	pathcounts[src] = 1
+	releaseTight(g, w, src, dists, npreds, make([]bool, nv), make([]uint32, 0, nv), nil, func(u, v uint32) {
+		pathcounts[v] += pathcounts[u]
+		if u < parents[v] {
+			parents[v] = u
+		}
+	})
+	return parents, pathcounts, preds
+}
+
+// UnitDijkstra performs a Dijkstra Shortest Paths calculation from vertex `src` with a weight of 1
+// on every edge and returns a DijkstraState. It visits vertices level by level, as BFS does, and is
+// faster than Dijkstra for unweighted graphs.
+// If `withpreds` is true, track all predecessors.
+func UnitDijkstra(g Graph, src uint32, withPreds bool) DijkstraState {
 	nv := g.NumVertices()
 	vertLevel := make([]uint32, nv)
 	for i := u0; i < nv; i++ {
@@ -52,17 +290,17 @@ func Dijkstra(g Graph, src uint32, weightFn func(uint32, uint32) float32, withPr
 
 	for i := range dists {
 		dists[i] = maxDist
+		parents[i] = NoParent
 	}
 
 	vertLevel[src] = 0
 	dists[src] = 0
-	parents[src] = src
 	pathcounts[src] = 1
 	curLevel = append(curLevel, src)
 	for len(curLevel) > 0 {
 		for _, u := range curLevel {
+			alt := min(maxDist, dists[u]+1)
 			for _, v := range g.OutNeighbors(u) {
-				alt := min(maxDist, dists[u]+weightFn(u, v))
 				if vertLevel[v] == unvisited { // if not visited
 					dists[v] = alt
 					parents[v] = u
@@ -72,32 +310,20 @@ func Dijkstra(g Graph, src uint32, weightFn func(uint32, uint32) float32, withPr
 					}
 					nextLevel = append(nextLevel, v)
 					vertLevel[v] = nLevel
-				} else {
-					if alt < dists[v] {
-						dists[v] = alt
-						parents[v] = u
-						pathcounts[v] = 0
-						if withPreds {
-							preds[v] = preds[v][:0]
-						}
-					}
-					if alt == dists[v] {
-						pathcounts[v] += pathcounts[u]
-						if withPreds {
-							preds[v] = append(preds[v], u)
-						}
+				} else if alt == dists[v] {
+					pathcounts[v] += pathcounts[u]
+					if withPreds {
+						preds[v] = append(preds[v], u)
 					}
 				}
 			}
 		}
-		fmt.Printf("completed level %d, size = %d\n", nLevel-1, len(nextLevel))
 		nLevel++
 		curLevel = curLevel[:0]
 		curLevel, nextLevel = nextLevel, curLevel
 		zuint32.SortBYOB(curLevel, nextLevel[:nv])
 	}
 	pathcounts[src] = 1
-	parents[src] = 0
 	if withPreds {
 		preds[src] = preds[src][:0]
 	}
@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDijkstraZeroWeightPathcounts(t *testing.T) {
+	// 0->1 and 0->2->1 are both shortest paths to 1, through the zero-weight edge 2->1.
+	g, err := NewWeightedDiGraph([]uint32{0, 0, 2, 1}, []uint32{1, 2, 1, 3}, []float32{1, 1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Dijkstra(g, 0, nil, true)
+	want := []uint32{1, 2, 1, 2}
+	for v, c := range want {
+		if s.Pathcounts[v] != c {
+			t.Errorf("Pathcounts[%d] = %d, expected %d", v, s.Pathcounts[v], c)
+		}
+	}
+	if len(s.Predecessors[1]) != 2 {
+		t.Errorf("Predecessors[1] = %v, expected [0 2]", s.Predecessors[1])
+	}
+}
+
+func TestUndirectedZeroWeightPathcounts(t *testing.T) {
+	// the zero-weight edge 1-2 is tight in both directions, but 0-1-2-3 is the only shortest path to 3.
+	g, err := NewWeighted([]uint32{0, 1, 2}, []uint32{1, 2, 3}, []float32{1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCounts := []uint32{1, 1, 1, 1}
+	wantParents := []uint32{NoParent, 0, 1, 2}
+	check := func(name string, s DijkstraState) {
+		t.Helper()
+		if !reflect.DeepEqual(s.Pathcounts, wantCounts) {
+			t.Errorf("%s Pathcounts = %v, expected %v", name, s.Pathcounts, wantCounts)
+		}
+		if !reflect.DeepEqual(s.Parents, wantParents) {
+			t.Errorf("%s Parents = %v, expected %v", name, s.Parents, wantParents)
+		}
+	}
+	check("Dijkstra", Dijkstra(g, 0, nil, false))
+	check("DeltaStepping", DeltaStepping(g, 0, nil, 1, false, 2))
+	s, err := BellmanFord(g, 0, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("BellmanFord", s)
+	if s, err = SPFA(g, 0, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	check("SPFA", s)
+	err = Johnson(g, nil, false, 1, func(src uint32, s DijkstraState) {
+		if src == 0 {
+			check("Johnson", s)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPathTo(t *testing.T) {
+	// a diamond 0 -> {1, 2} -> 3, and 4 unreachable.
+	g, err := NewDiGraph([]uint32{0, 0, 1, 2, 4}, []uint32{1, 2, 3, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Dijkstra(g, 0, nil, true)
+	if !s.HasPathTo(3) || s.HasPathTo(4) {
+		t.Errorf("HasPathTo(3), HasPathTo(4) = %v, %v, expected true, false", s.HasPathTo(3), s.HasPathTo(4))
+	}
+	if path := s.PathTo(3); !reflect.DeepEqual(path, []uint32{0, 1, 3}) {
+		t.Errorf("PathTo(3) = %v, expected [0 1 3]", path)
+	}
+	if path := s.PathTo(0); !reflect.DeepEqual(path, []uint32{0}) {
+		t.Errorf("PathTo(0) = %v, expected [0]", path)
+	}
+	if path := s.PathTo(4); path != nil {
+		t.Errorf("PathTo(4) = %v, expected nil", path)
+	}
+}
+
+// allPaths collects the paths of AllPathsTo(v).
+func allPaths(t *testing.T, s DijkstraState, v uint32) [][]uint32 {
+	t.Helper()
+	it, err := s.AllPathsTo(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := [][]uint32{}
+	for !it.Done() {
+		paths = append(paths, it.Next())
+	}
+	return paths
+}
+
+func TestAllPathsTo(t *testing.T) {
+	g, err := NewDiGraph([]uint32{0, 0, 1, 2, 4}, []uint32{1, 2, 3, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Dijkstra(g, 0, nil, true)
+	if paths := allPaths(t, s, 3); !reflect.DeepEqual(paths, [][]uint32{{0, 1, 3}, {0, 2, 3}}) {
+		t.Errorf("AllPathsTo(3) = %v, expected [[0 1 3] [0 2 3]]", paths)
+	}
+	if paths := allPaths(t, s, 0); !reflect.DeepEqual(paths, [][]uint32{{0}}) {
+		t.Errorf("AllPathsTo(0) = %v, expected [[0]]", paths)
+	}
+	if paths := allPaths(t, s, 4); len(paths) != 0 {
+		t.Errorf("AllPathsTo(4) = %v, expected no paths", paths)
+	}
+	if _, err := Dijkstra(g, 0, nil, false).AllPathsTo(3); err == nil {
+		t.Error("AllPathsTo without predecessors returned no error")
+	}
+
+	// without zero weights, every shortest path is counted in Pathcounts.
+	r := rand.New(rand.NewSource(12))
+	for i := 0; i < 50; i++ {
+		nv := 1 + r.Intn(20)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(3*nv))
+		for j := range ws {
+			ws[j] = 1 + float32(int(ws[j])%3)
+		}
+		g, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := Dijkstra(g, 0, nil, true)
+		for v := u0; v < g.NumVertices(); v++ {
+			paths := allPaths(t, s, v)
+			if uint32(len(paths)) != s.Pathcounts[v] {
+				t.Fatalf("%v: AllPathsTo(%d) returned %d paths, expected %d", g, v, len(paths), s.Pathcounts[v])
+			}
+			seen := map[string]bool{}
+			for _, p := range paths {
+				if p[0] != 0 || p[len(p)-1] != v || seen[pathKey(p)] {
+					t.Fatalf("%v: AllPathsTo(%d) returned %v", g, v, p)
+				}
+				seen[pathKey(p)] = true
+				if c := pathCost(newWeigher(g, nil), p); c != s.Dists[v] {
+					t.Fatalf("%v: AllPathsTo(%d) returned %v of cost %v, expected %v", g, v, p, c, s.Dists[v])
+				}
+			}
+		}
+	}
+}
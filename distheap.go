@@ -0,0 +1,58 @@
+package graph
+
+// distItem is a vertex and its tentative distance.
+type distItem struct {
+	dist float32
+	v    uint32
+}
+
+// distHeap is a binary min-heap of vertices keyed by distance. Vertices are not
+// updated in place; callers push a vertex again when its distance decreases and
+// skip stale entries when they are popped.
+type distHeap struct {
+	items []distItem
+}
+
+// Len returns the number of entries in the heap.
+func (h *distHeap) Len() int {
+	return len(h.items)
+}
+
+// Push adds vertex v with distance d to the heap.
+func (h *distHeap) Push(v uint32, d float32) {
+	h.items = append(h.items, distItem{dist: d, v: v})
+	i := len(h.items) - 1
+	for i > 0 {
+		p := (i - 1) / 2
+		if h.items[p].dist <= h.items[i].dist {
+			break
+		}
+		h.items[p], h.items[i] = h.items[i], h.items[p]
+		i = p
+	}
+}
+
+// Pop removes and returns the vertex with the smallest distance, and that distance.
+func (h *distHeap) Pop() (uint32, float32) {
+	top := h.items[0]
+	last := len(h.items) - 1
+	h.items[0] = h.items[last]
+	h.items = h.items[:last]
+	i := 0
+	for {
+		l := 2*i + 1
+		if l >= last {
+			break
+		}
+		m := l
+		if r := l + 1; r < last && h.items[r].dist < h.items[l].dist {
+			m = r
+		}
+		if h.items[i].dist <= h.items[m].dist {
+			break
+		}
+		h.items[i], h.items[m] = h.items[m], h.items[i]
+		i = m
+	}
+	return top.v, top.dist
+}
@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/egonelbre/async"
+	"github.com/sbromberger/bitvec"
+	"github.com/shawnsmithdev/zermelo/zuint32"
+)
+
+const (
+	// DefaultAlpha is the default value of DOBFSOptions.Alpha.
+	DefaultAlpha = 15
+	// DefaultBeta is the default value of DOBFSOptions.Beta.
+	DefaultBeta = 18
+)
+
+// DOBFSOptions tunes when direction-optimizing BFS switches between top-down steps, which
+// scan the out neighbors of the frontier, and bottom-up steps, which scan the in neighbors
+// of unvisited vertices for a vertex in the frontier. Zero values are replaced by the defaults.
+type DOBFSOptions struct {
+	// Alpha: switch from top-down to bottom-up when the number of edges out of the frontier
+	// exceeds the number of edges out of unvisited vertices divided by Alpha.
+	Alpha float64
+	// Beta: switch from bottom-up back to top-down when the frontier is shrinking and has
+	// fewer than NumVertices / Beta vertices.
+	Beta float64
+}
+
+func (o DOBFSOptions) withDefaults() DOBFSOptions {
+	if o.Alpha <= 0 {
+		o.Alpha = DefaultAlpha
+	}
+	if o.Beta <= 0 {
+		o.Beta = DefaultBeta
+	}
+	return o
+}
+
+// nextBottomUp returns true if the next level should be explored bottom-up, given whether the
+// current level was, the number of edges out of the frontier mf, the number of edges out of
+// unvisited vertices mu, and the sizes of the frontier nf and of the previous frontier prevNf.
+func (o DOBFSOptions) nextBottomUp(bottomUp bool, mf, mu uint64, nf, prevNf, nv uint32) bool {
+	if bottomUp {
+		return nf >= prevNf || float64(nf) > float64(nv)/o.Beta
+	}
+	return float64(mf) > float64(mu)/o.Alpha
+}
+
+// adjacencyEntries returns the number of entries in the out neighbor lists of g.
+func adjacencyEntries(g Graph) uint64 {
+	if g.IsDirected() {
+		return g.NumEdges()
+	}
+	return 2 * g.NumEdges()
+}
+
+// DirectionOptimizingBFS computes a vector of levels from src, switching between top-down and
+// bottom-up steps as described by opts, and returns a vector of vertices visited in order of
+// level along with a vector of levels indexed by vertex, as BFS does. It is faster than BFS
+// on graphs with a low diameter.
+func DirectionOptimizingBFS(g Graph, src uint32, opts DOBFSOptions) (vertexList, vertLevel []uint32) {
+	opts = opts.withDefaults()
+	nv := g.NumVertices()
+	vertLevel = make([]uint32, nv)
+	for i := uint32(0); i < nv; i++ {
+		vertLevel[i] = unvisited
+	}
+
+	visited := bitvec.NewBitVec(nv)
+	curLevel := make([]uint32, 0, nv)
+	nextLevel := make([]uint32, 0, nv)
+	nLevel := uint32(1)
+	vertLevel[src] = 0
+	visited.TrySet(src)
+	curLevel = append(curLevel, src)
+	vertexList = make([]uint32, 0, nv)
+	vertexList = append(vertexList, src)
+
+	mf := uint64(g.OutDegree(src))
+	mu := adjacencyEntries(g) - mf
+	prevNf := u0
+	bottomUp := false
+	for len(curLevel) > 0 {
+		nf := uint32(len(curLevel))
+		bottomUp = opts.nextBottomUp(bottomUp, mf, mu, nf, prevNf, nv)
+		prevNf = nf
+		if bottomUp {
+			for v := u0; v < nv; v++ {
+				if vertLevel[v] != unvisited {
+					continue
+				}
+				for _, u := range g.InNeighbors(v) {
+					if vertLevel[u] == nLevel-1 {
+						visited.TrySet(v)
+						nextLevel = append(nextLevel, v)
+						vertLevel[v] = nLevel
+						vertexList = append(vertexList, v)
+						break
+					}
+				}
+			}
+		} else {
+			for _, v := range curLevel {
+				for _, neighbor := range g.OutNeighbors(v) {
+					if visited.TrySet(neighbor) {
+						nextLevel = append(nextLevel, neighbor)
+						vertLevel[neighbor] = nLevel
+						vertexList = append(vertexList, neighbor)
+					}
+				}
+			}
+		}
+		mf = 0
+		for _, v := range nextLevel {
+			mf += uint64(g.OutDegree(v))
+		}
+		if mf < mu {
+			mu -= mf
+		} else {
+			mu = 0
+		}
+		nLevel++
+		curLevel = curLevel[:0]
+		curLevel, nextLevel = nextLevel, curLevel
+		if !bottomUp { // bottom-up steps produce sorted levels
+			zuint32.SortBYOB(curLevel, nextLevel[:nv])
+		}
+	}
+	return vertexList, vertLevel
+}
+
+// processLevelBottomUp scans the unvisited vertices in [low, high) for an in neighbor at level-1,
+// writing those found to nextLevel.
+func processLevelBottomUp(g Graph, low, high uint32, level uint32, nextLevel *Frontier, visited *bitvec.ABitVec, vertLevel []uint32) {
+	writeLow, writeHigh := u0, u0
+	for v := low; v < high; v++ {
+		if atomic.LoadUint32(&vertLevel[v]) != unvisited {
+			continue
+		}
+		for _, u := range g.InNeighbors(v) {
+			if atomic.LoadUint32(&vertLevel[u]) == level-1 {
+				visited.TrySet(v)
+				atomic.StoreUint32(&vertLevel[v], level)
+				nextLevel.Write(&writeLow, &writeHigh, v)
+				break
+			}
+		}
+	}
+
+	for i := writeLow; i < writeHigh; i++ {
+		nextLevel.Data[i] = EmptySentinel // ensure the rest of the nextLevel block is "empty" using the sentinel
+	}
+}
+
+// ParallelDirectionOptimizingBFS computes a vector of levels from src in parallel, switching between
+// top-down and bottom-up steps as described by opts, and returns a vector of vertices visited in order
+// of level along with a vector of levels indexed by vertex, as ParallelBFS does.
+func ParallelDirectionOptimizingBFS(g Graph, src uint32, procs int, opts DOBFSOptions) (vertexList, vertLevel []uint32) {
+	opts = opts.withDefaults()
+	N := g.NumVertices()
+	vertLevel = make([]uint32, N)
+	for i := range vertLevel {
+		vertLevel[i] = unvisited
+	}
+	visited := bitvec.NewABitVec(N)
+
+	maxSize := N + (MaxBlockSize * uint32(procs))
+	currLevel := &Frontier{make([]uint32, 0, maxSize), 0}
+	nextLevel := &Frontier{make([]uint32, maxSize), 0}
+
+	currentLevel := uint32(1)
+	vertLevel[src] = 0
+	visited.TrySet(src)
+	vertexList = make([]uint32, 0, N)
+	vertexList = append(vertexList, src)
+
+	currLevel.Data = append(currLevel.Data, src)
+
+	mf := uint64(g.OutDegree(src))
+	mu := adjacencyEntries(g) - mf
+	nf, prevNf := uint32(1), u0
+	bottomUp := false
+
+	wait := make(chan struct{})
+	for len(currLevel.Data) > 0 { // while we have vertices in currentLevel
+		bottomUp = opts.nextBottomUp(bottomUp, mf, mu, nf, prevNf, N)
+		prevNf = nf
+
+		if bottomUp {
+			level := currentLevel
+			async.BlockIter(int(N), procs, func(low, high int) {
+				processLevelBottomUp(g, uint32(low), uint32(high), level, nextLevel, &visited, vertLevel)
+			})
+		} else {
+			async.Spawn(procs, func(i int) { // spawn `procs` goroutines to process vertices in this level,
+				runtime.LockOSThread() // using currLevel as the work queue. Make sure only one goroutine per thread.
+				processLevel(g, currLevel, nextLevel, &visited, nil)
+			}, func() { wait <- struct{}{} })
+
+			<-wait // this is equivalent to using a WaitGroup but uses a single channel message instead.
+		}
+
+		nextLevel.Data = nextLevel.Data[:nextLevel.Head] // "truncate" nextLevel.Data to just the valid data...
+		// ... we need to do this because Frontier.ReadNext uses `len`.
+
+		// sort nextLevel by block, and count the vertices in it and the edges out of them.
+		mf, nf = 0, 0
+		async.BlockIter(int(nextLevel.Head), procs, func(low, high int) {
+			zuint32.SortBYOB(nextLevel.Data[low:high], currLevel.Data[low:high])
+			blockEdges, blockCount := uint64(0), u0
+			for _, v := range nextLevel.Data[low:high] {
+				if v == EmptySentinel {
+					break
+				}
+				vertLevel[v] = currentLevel
+				blockEdges += uint64(g.OutDegree(v))
+				blockCount++
+			}
+			atomic.AddUint64(&mf, blockEdges)
+			atomic.AddUint32(&nf, blockCount)
+		})
+		if mf < mu {
+			mu -= mf
+		} else {
+			mu = 0
+		}
+
+		for _, v := range nextLevel.Data {
+			if v != EmptySentinel {
+				vertexList = append(vertexList, v)
+			}
+		}
+
+		currentLevel++
+		currLevel, nextLevel = nextLevel, currLevel
+		currLevel.Head = 0 // start reading from 0
+		// reset buffer for next level
+		nextLevel.Data = nextLevel.Data[:maxSize:maxSize] // resize the buffer to `maxSize` elements. We don't care what's in it, because...
+		nextLevel.Head = 0                                // ... we start writing to index 0.
+	}
+	return vertexList, vertLevel
+}
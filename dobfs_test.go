@@ -0,0 +1,37 @@
+package graph
+
+import "testing"
+
+// dobfsOptions favor the default switching, bottom-up steps and top-down steps.
+var dobfsOptions = []DOBFSOptions{{}, {Alpha: 1e9, Beta: 1e9}, {Alpha: 1e-9, Beta: 1e-9}}
+
+func TestDirectionOptimizingBFS(t *testing.T) {
+	g, wantSets, wantLevel := bfsTestGraph(t)
+	for _, opts := range dobfsOptions {
+		list, level := DirectionOptimizingBFS(g, 0, opts)
+		checkSame(t, g, "DirectionOptimizingBFS levels", 1, level, wantLevel)
+		checkSame(t, g, "DirectionOptimizingBFS vertices", 1, levelSets(list, level), wantSets)
+		forProcs(func(procs int) {
+			list, level := ParallelDirectionOptimizingBFS(g, 0, procs, opts)
+			checkSame(t, g, "ParallelDirectionOptimizingBFS levels", procs, level, wantLevel)
+			checkSame(t, g, "ParallelDirectionOptimizingBFS vertices", procs, levelSets(list, level), wantSets)
+		})
+	}
+}
+
+func TestDirectionOptimizingBFSMatchesBFS(t *testing.T) {
+	for _, g := range parallelTestGraphs(t, 7) {
+		wantList, wantLevel := BFS(g, 0)
+		wantSets := levelSets(wantList, wantLevel)
+		for _, opts := range dobfsOptions {
+			list, level := DirectionOptimizingBFS(g, 0, opts)
+			checkSame(t, g, "DirectionOptimizingBFS levels", 1, level, wantLevel)
+			checkSame(t, g, "DirectionOptimizingBFS vertices", 1, levelSets(list, level), wantSets)
+			forProcs(func(procs int) {
+				list, level := ParallelDirectionOptimizingBFS(g, 0, procs, opts)
+				checkSame(t, g, "ParallelDirectionOptimizingBFS levels", procs, level, wantLevel)
+				checkSame(t, g, "ParallelDirectionOptimizingBFS vertices", procs, levelSets(list, level), wantSets)
+			})
+		}
+	}
+}
@@ -62,3 +62,14 @@ type Graph interface {
 	Edges() EdgeIter
 	Vertices() VertexIter
 }
+
+// WeightedGraph is a Graph that stores a weight for each edge.
+type WeightedGraph interface {
+	Graph
+	// OutEdgesWeighted returns the out neighbors of vertex u and the weights of the corresponding edges.
+	OutEdgesWeighted(u uint32) ([]uint32, []float32)
+	// InEdgesWeighted returns the in neighbors of vertex u and the weights of the corresponding edges.
+	InEdgesWeighted(u uint32) ([]uint32, []float32)
+	// Weight returns the weight of the edge from u to v, and whether the edge exists.
+	Weight(u, v uint32) (float32, bool)
+}
@@ -0,0 +1,139 @@
+package graph
+
+// pathMask hides the vertices of the root of a spur path and the edges out of the spur vertex
+// already taken by shorter paths from the search for the spur path.
+type pathMask struct {
+	spur     uint32
+	vertices []bool // vertices the spur path may not enter
+	edges    []bool // heads of the masked edges out of spur
+}
+
+func (m *pathMask) blocked(u, v uint32) bool {
+	return m.vertices[v] || (u == m.spur && m.edges[v])
+}
+
+// pathCost returns the sum of the weights of the edges along path.
+func pathCost(w weigher, path []uint32) float32 {
+	cost := float32(0)
+	for i := 1; i < len(path); i++ {
+		cost += w.lookup(path[i-1], path[i])
+	}
+	return cost
+}
+
+// costedPath is a candidate path and its cost.
+type costedPath struct {
+	path []uint32
+	cost float32
+}
+
+// less orders paths by cost, then by number of vertices, then lexicographically, so that
+// KShortestPaths returns paths of equal cost in a deterministic order.
+func (p costedPath) less(q costedPath) bool {
+	if p.cost != q.cost {
+		return p.cost < q.cost
+	}
+	if len(p.path) != len(q.path) {
+		return len(p.path) < len(q.path)
+	}
+	for i := range p.path {
+		if p.path[i] != q.path[i] {
+			return p.path[i] < q.path[i]
+		}
+	}
+	return false
+}
+
+// pathKey returns a map key identifying path.
+func pathKey(path []uint32) string {
+	b := make([]byte, 4*len(path))
+	for i, v := range path {
+		b[4*i], b[4*i+1], b[4*i+2], b[4*i+3] = byte(v), byte(v>>8), byte(v>>16), byte(v>>24)
+	}
+	return string(b)
+}
+
+// KShortestPaths returns up to k shortest loopless paths from `src` to `dst` and their costs, in
+// order of increasing cost, using Yen's algorithm. Weights must be non-negative.
+func KShortestPaths(g Graph, src, dst uint32, k int, weightFn func(uint32, uint32) float32) ([][]uint32, []float32) {
+	paths, costs := [][]uint32{}, []float32{}
+	if k <= 0 {
+		return paths, costs
+	}
+	w := newWeigher(g, weightFn)
+	first, cost, found := aStar(g, src, dst, w, nil, nil)
+	if !found {
+		return paths, costs
+	}
+	paths = append(paths, first)
+	costs = append(costs, cost)
+
+	nv := g.NumVertices()
+	mask := pathMask{vertices: make([]bool, nv), edges: make([]bool, nv)}
+	candidates := []costedPath{}
+	seen := map[string]struct{}{pathKey(paths[0]): {}}
+	for len(paths) < k {
+		prev := paths[len(paths)-1]
+		for i := 0; i+1 < len(prev); i++ {
+			spur, root := prev[i], prev[:i+1]
+			mask.spur = spur
+			for _, p := range paths {
+				if len(p) > i+1 && equalPrefix(p, root) {
+					mask.edges[p[i+1]] = true
+				}
+			}
+			for _, v := range root[:i] {
+				mask.vertices[v] = true
+			}
+			spurPath, _, found := aStar(g, spur, dst, w, nil, mask.blocked)
+			for _, p := range paths {
+				if len(p) > i+1 {
+					mask.edges[p[i+1]] = false
+				}
+			}
+			for _, v := range root[:i] {
+				mask.vertices[v] = false
+			}
+			if !found {
+				continue
+			}
+
+			path := make([]uint32, 0, len(root)+len(spurPath))
+			path = append(path, root[:i]...)
+			path = append(path, spurPath...)
+			key := pathKey(path)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			candidates = append(candidates, costedPath{path: path, cost: pathCost(w, path)})
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		best := 0
+		for i := range candidates {
+			if candidates[i].less(candidates[best]) {
+				best = i
+			}
+		}
+		paths = append(paths, candidates[best].path)
+		costs = append(costs, candidates[best].cost)
+		candidates[best] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+	}
+	return paths, costs
+}
+
+// equalPrefix returns true if path starts with prefix.
+func equalPrefix(path, prefix []uint32) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i, v := range prefix {
+		if path[i] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// simplePathCosts returns the costs of all loopless paths from src to dst in increasing order.
+func simplePathCosts(g Graph, src, dst uint32) []float32 {
+	w := newWeigher(g, nil)
+	costs := []float32{}
+	onPath := make([]bool, g.NumVertices())
+	var extend func(path []uint32)
+	extend = func(path []uint32) {
+		u := path[len(path)-1]
+		if u == dst {
+			costs = append(costs, pathCost(w, path))
+			return
+		}
+		onPath[u] = true
+		for _, v := range g.OutNeighbors(u) {
+			if !onPath[v] {
+				extend(append(path, v))
+			}
+		}
+		onPath[u] = false
+	}
+	extend([]uint32{src})
+	sort.Slice(costs, func(i, j int) bool { return costs[i] < costs[j] })
+	return costs
+}
+
+func TestKShortestPaths(t *testing.T) {
+	// the paths from 0 to 3 are 0-1-2-3 of cost 3, 0-2-3 of cost 4 and 0-1-3 of cost 5.
+	g, err := NewWeightedDiGraph([]uint32{0, 0, 1, 1, 2, 4}, []uint32{1, 2, 2, 3, 3, 4}, []float32{1, 3, 1, 4, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, costs := KShortestPaths(g, 0, 3, 5, nil)
+	wantPaths := [][]uint32{{0, 1, 2, 3}, {0, 2, 3}, {0, 1, 3}}
+	if !reflect.DeepEqual(paths, wantPaths) || !reflect.DeepEqual(costs, []float32{3, 4, 5}) {
+		t.Errorf("KShortestPaths(0, 3, 5) = %v, %v, expected %v, [3 4 5]", paths, costs, wantPaths)
+	}
+	if paths, costs := KShortestPaths(g, 0, 3, 2, nil); !reflect.DeepEqual(paths, wantPaths[:2]) || !reflect.DeepEqual(costs, []float32{3, 4}) {
+		t.Errorf("KShortestPaths(0, 3, 2) = %v, %v, expected %v, [3 4]", paths, costs, wantPaths[:2])
+	}
+	if paths, costs := KShortestPaths(g, 0, 4, 3, nil); len(paths) != 0 || len(costs) != 0 {
+		t.Errorf("KShortestPaths(0, 4, 3) = %v, %v, expected no paths", paths, costs)
+	}
+	if paths, costs := KShortestPaths(g, 0, 3, 0, nil); len(paths) != 0 || len(costs) != 0 {
+		t.Errorf("KShortestPaths(0, 3, 0) = %v, %v, expected no paths", paths, costs)
+	}
+	if paths, costs := KShortestPaths(g, 2, 2, 3, nil); !reflect.DeepEqual(paths, [][]uint32{{2}}) || !reflect.DeepEqual(costs, []float32{0}) {
+		t.Errorf("KShortestPaths(2, 2, 3) = %v, %v, expected [[2]], [0]", paths, costs)
+	}
+}
+
+func TestKShortestPathsMatchesEnumeration(t *testing.T) {
+	for _, g := range randomGraphs(t, 16, 40, 7) {
+		nv := g.NumVertices()
+		w := newWeigher(g, nil)
+		for src := u0; src < nv; src++ {
+			for dst := u0; dst < nv; dst++ {
+				want := simplePathCosts(g, src, dst)
+				k := len(want) + 1
+				paths, costs := KShortestPaths(g, src, dst, k, nil)
+				if !reflect.DeepEqual(costs, want) {
+					t.Fatalf("%v: KShortestPaths(%d, %d, %d) costs = %v, expected %v", g, src, dst, k, costs, want)
+				}
+				seen := map[string]bool{}
+				for i, path := range paths {
+					visited := map[uint32]bool{}
+					for _, v := range path {
+						if visited[v] {
+							t.Fatalf("%v: path %v from %d to %d has a loop", g, path, src, dst)
+						}
+						visited[v] = true
+					}
+					for j := 1; j < len(path); j++ {
+						if !g.HasEdge(path[j-1], path[j]) {
+							t.Fatalf("%v: path %v from %d to %d has no edge %d-%d", g, path, src, dst, path[j-1], path[j])
+						}
+					}
+					if path[0] != src || path[len(path)-1] != dst || pathCost(w, path) != costs[i] || seen[pathKey(path)] {
+						t.Fatalf("%v: KShortestPaths(%d, %d, %d) = %v, %v", g, src, dst, k, paths, costs)
+					}
+					seen[pathKey(path)] = true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"github.com/sbromberger/bitvec"
+	"github.com/shawnsmithdev/zermelo/zuint32"
+)
+
+// MultiSourceBFS computes the distance in hops from the nearest of the vertices in srcs to
+// every vertex. It returns a vector indexed by vertex of the nearest source and a vector
+// indexed by vertex of the distance to it. Vertices that are not reachable from any source
+// have a nearest source and distance of math.MaxUint32. Ties between sources at the same
+// distance are broken arbitrarily.
+func MultiSourceBFS(g Graph, srcs []uint32) (nearest, dists []uint32) {
+	nv := g.NumVertices()
+	nearest = make([]uint32, nv)
+	dists = make([]uint32, nv)
+	for i := uint32(0); i < nv; i++ {
+		nearest[i] = unvisited
+		dists[i] = unvisited
+	}
+
+	visited := bitvec.NewBitVec(nv)
+	curLevel := make([]uint32, 0, nv)
+	nextLevel := make([]uint32, 0, nv)
+	nLevel := uint32(1)
+	for _, src := range srcs {
+		if visited.TrySet(src) {
+			dists[src] = 0
+			nearest[src] = src
+			curLevel = append(curLevel, src)
+		}
+	}
+	zuint32.SortBYOB(curLevel, nextLevel[:len(curLevel)])
+	for len(curLevel) > 0 {
+		for _, v := range curLevel {
+			for _, neighbor := range g.OutNeighbors(v) {
+				if visited.TrySet(neighbor) {
+					nextLevel = append(nextLevel, neighbor)
+					dists[neighbor] = nLevel
+					nearest[neighbor] = nearest[v]
+				}
+			}
+		}
+		nLevel++
+		curLevel = curLevel[:0]
+		curLevel, nextLevel = nextLevel, curLevel
+		zuint32.SortBYOB(curLevel, nextLevel[:nv])
+	}
+	return nearest, dists
+}
+
+// ParallelMultiSourceBFS computes the distance in hops from the nearest of the vertices in srcs
+// to every vertex in parallel, and returns the same vectors as MultiSourceBFS.
+func ParallelMultiSourceBFS(g Graph, srcs []uint32, procs int) (nearest, dists []uint32) {
+	nv := g.NumVertices()
+	nearest = make([]uint32, nv)
+	parents := make([]uint32, nv)
+	for i := range nearest {
+		nearest[i] = unvisited
+		parents[i] = NoParent
+	}
+	for _, src := range srcs {
+		nearest[src] = src
+	}
+	_, dists = parallelBFS(g, srcs, procs, NoDepthLimit, parents, nearest)
+	return nearest, dists
+}
+
+// KHop returns the vertices at most k hops from src, including src, in order of distance.
+func KHop(g Graph, src uint32, k uint32) []uint32 {
+	vertexList, _, _ := VisitBFS(g, src, nil, k)
+	return vertexList
+}
+
+// ParallelKHop returns the vertices at most k hops from src, including src, in order of distance.
+// The vertices are found in parallel, and vertices at the same distance may be in a different
+// order than in KHop.
+func ParallelKHop(g Graph, src uint32, k uint32, procs int) []uint32 {
+	vertexList, _ := parallelBFS(g, []uint32{src}, procs, k, nil, nil)
+	return vertexList
+}
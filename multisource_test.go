@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMultiSourceBFS(t *testing.T) {
+	// the path 0-1-2-3-4-5 and the isolated vertex 6.
+	g, err := New([]uint32{0, 1, 2, 3, 4, 6}, []uint32{1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNearest := []uint32{0, 0, 0, 5, 5, 5, unvisited}
+	wantDists := []uint32{0, 1, 2, 2, 1, 0, unvisited}
+	nearest, dists := MultiSourceBFS(g, []uint32{0, 5})
+	checkSame(t, g, "MultiSourceBFS nearest", 1, nearest, wantNearest)
+	checkSame(t, g, "MultiSourceBFS dists", 1, dists, wantDists)
+	forProcs(func(procs int) {
+		nearest, dists := ParallelMultiSourceBFS(g, []uint32{0, 5}, procs)
+		checkSame(t, g, "ParallelMultiSourceBFS nearest", procs, nearest, wantNearest)
+		checkSame(t, g, "ParallelMultiSourceBFS dists", procs, dists, wantDists)
+	})
+}
+
+// checkNearest fails unless dists holds the distance from the nearest of srcs to each vertex,
+// given the levels of a BFS from each source, and nearest one of the sources at that distance.
+func checkNearest(t *testing.T, g Graph, name string, srcs []uint32, levels [][]uint32, nearest, dists []uint32) {
+	t.Helper()
+	for v := range dists {
+		want := uint32(unvisited)
+		for _, l := range levels {
+			if l[v] < want {
+				want = l[v]
+			}
+		}
+		if dists[v] != want {
+			t.Fatalf("%v: %s distance of %d is %d, expected %d", g, name, v, dists[v], want)
+		}
+		if want == unvisited {
+			if nearest[v] != unvisited {
+				t.Fatalf("%v: %s nearest source of unreached %d is %d", g, name, v, nearest[v])
+			}
+			continue
+		}
+		i := sort.Search(len(srcs), func(i int) bool { return srcs[i] >= nearest[v] })
+		if i == len(srcs) || srcs[i] != nearest[v] || levels[i][v] != want {
+			t.Fatalf("%v: %s nearest source of %d is %d, which is not %d away", g, name, v, nearest[v], want)
+		}
+	}
+}
+
+func TestMultiSourceBFSMatchesBFS(t *testing.T) {
+	for _, g := range parallelTestGraphs(t, 10) {
+		nv := g.NumVertices()
+		srcs := []uint32{0, nv / 2, nv - 1, nv / 2}
+		levels := make([][]uint32, len(srcs))
+		sort.Slice(srcs, func(i, j int) bool { return srcs[i] < srcs[j] })
+		for i, src := range srcs {
+			_, levels[i] = BFS(g, src)
+		}
+		nearest, dists := MultiSourceBFS(g, srcs)
+		checkNearest(t, g, "MultiSourceBFS", srcs, levels, nearest, dists)
+		forProcs(func(procs int) {
+			nearest, dists := ParallelMultiSourceBFS(g, srcs, procs)
+			checkNearest(t, g, "ParallelMultiSourceBFS", srcs, levels, nearest, dists)
+		})
+	}
+}
+
+func TestKHop(t *testing.T) {
+	g, err := New([]uint32{0, 1, 2, 3, 4, 6}, []uint32{1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := KHop(g, 2, 1); !reflect.DeepEqual(got, []uint32{2, 1, 3}) {
+		t.Errorf("KHop(2, 1) = %v, expected [2 1 3]", got)
+	}
+	if got := KHop(g, 6, 3); !reflect.DeepEqual(got, []uint32{6}) {
+		t.Errorf("KHop(6, 3) = %v, expected [6]", got)
+	}
+
+	for _, g := range parallelTestGraphs(t, 11) {
+		_, level := BFS(g, 0)
+		for _, k := range []uint32{0, 1, 2, 5} {
+			want := []uint32{}
+			for v, l := range level {
+				if l <= k {
+					want = append(want, uint32(v))
+				}
+			}
+			got := KHop(g, 0, k)
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			checkSame(t, g, "KHop", 1, got, want)
+			forProcs(func(procs int) {
+				got := ParallelKHop(g, 0, k, procs)
+				sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+				checkSame(t, g, "ParallelKHop", procs, got, want)
+			})
+		}
+	}
+}
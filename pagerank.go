@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ErrNotConverged is returned by power iterations that do not converge within the maximum
+// number of iterations. The PowerIterResult returned with it holds the last iterate.
+var ErrNotConverged = errors.New("power iteration did not converge")
+
+// PowerIterResult holds the result of a power iteration.
+type PowerIterResult struct {
+	Scores     []float64 // the score of each vertex
+	Iterations int       // the number of iterations performed
+	Residual   float64   // the L1 norm of the change in Scores in the last iteration
+	Converged  bool      // whether Residual fell below the tolerance
+}
+
+// blockSum calls fn on blocks of [0, n) as blockIter does and returns the sum of the results.
+func blockSum(n int, procs int, fn func(low, high int) float64) float64 {
+	var mu sync.Mutex
+	sum := 0.0
+	blockIter(n, procs, func(low, high int) {
+		s := fn(low, high)
+		mu.Lock()
+		sum += s
+		mu.Unlock()
+	})
+	return sum
+}
+
+// probabilityVector checks that x, named `name` in errors, has a non-negative entry for each of
+// nv vertices with a positive sum and returns a copy scaled to sum to 1, or a uniform vector if
+// x is nil.
+func probabilityVector(x []float64, nv uint32, name string) ([]float64, error) {
+	p := make([]float64, nv)
+	if x == nil {
+		for v := range p {
+			p[v] = 1 / float64(nv)
+		}
+		return p, nil
+	}
+	if len(x) != int(nv) {
+		return nil, fmt.Errorf("%s vector must have an entry for each vertex", name)
+	}
+	sum := 0.0
+	for _, xv := range x {
+		if xv < 0 || math.IsNaN(xv) {
+			return nil, fmt.Errorf("%s vector must be non-negative", name)
+		}
+		sum += xv
+	}
+	if sum <= 0 || math.IsInf(sum, 0) {
+		return nil, fmt.Errorf("%s vector must have a positive, finite sum", name)
+	}
+	for v, xv := range x {
+		p[v] = xv / sum
+	}
+	return p, nil
+}
+
+// pageRank performs the PageRank power iteration with teleport vector t, using `procs`
+// goroutines. Each iteration pulls the scores of the in neighbors of each vertex, so vertices
+// are updated independently. The scores of dangling vertices, which have no out edges, are
+// spread according to t.
+func pageRank(g Graph, t []float64, damping, tol float64, maxIter, procs int) (PowerIterResult, error) {
+	nv := g.NumVertices()
+	x := make([]float64, nv)
+	copy(x, t)
+	next := make([]float64, nv)
+	contrib := make([]float64, nv)
+
+	res := PowerIterResult{Scores: x}
+	for res.Iterations < maxIter {
+		dangling := blockSum(int(nv), procs, func(low, high int) float64 {
+			d := 0.0
+			for u := uint32(low); u < uint32(high); u++ {
+				if deg := g.OutDegree(u); deg > 0 {
+					contrib[u] = x[u] / float64(deg)
+				} else {
+					d += x[u]
+				}
+			}
+			return d
+		})
+		res.Residual = blockSum(int(nv), procs, func(low, high int) float64 {
+			r := 0.0
+			for v := uint32(low); v < uint32(high); v++ {
+				sum := 0.0
+				for _, u := range g.InNeighbors(v) {
+					sum += contrib[u]
+				}
+				next[v] = damping*sum + (damping*dangling+1-damping)*t[v]
+				r += math.Abs(next[v] - x[v])
+			}
+			return r
+		})
+		x, next = next, x
+		res.Scores = x
+		res.Iterations++
+		if res.Residual < tol {
+			res.Converged = true
+			return res, nil
+		}
+	}
+	return res, ErrNotConverged
+}
+
+// PageRank computes the PageRank of every vertex: the probability of being at the vertex after
+// a long random walk that follows a random out edge with probability `damping`, usually 0.85,
+// and jumps to a random vertex otherwise, or when it reaches a vertex with no out edges.
+// It iterates until the L1 norm of the change in scores is less than `tol`, or `maxIter` times,
+// in which case the result is returned with ErrNotConverged. The scores sum to 1.
+func PageRank(g Graph, damping, tol float64, maxIter int) (PowerIterResult, error) {
+	return ParallelPageRank(g, damping, tol, maxIter, 1)
+}
+
+// PersonalizedPageRank computes PageRank as PageRank does, but random jumps go to each vertex v
+// with a probability proportional to teleport[v] rather than uniformly. teleport must have a
+// non-negative entry for each vertex and a positive sum.
+func PersonalizedPageRank(g Graph, teleport []float64, damping, tol float64, maxIter int) (PowerIterResult, error) {
+	return ParallelPersonalizedPageRank(g, teleport, damping, tol, maxIter, 1)
+}
+
+// ParallelPageRank computes PageRank as PageRank does, partitioning the vertices across
+// `procs` goroutines in each iteration.
+func ParallelPageRank(g Graph, damping, tol float64, maxIter int, procs int) (PowerIterResult, error) {
+	return ParallelPersonalizedPageRank(g, nil, damping, tol, maxIter, procs)
+}
+
+// ParallelPersonalizedPageRank computes personalized PageRank as PersonalizedPageRank does,
+// partitioning the vertices across `procs` goroutines in each iteration. A nil teleport vector
+// is uniform.
+func ParallelPersonalizedPageRank(g Graph, teleport []float64, damping, tol float64, maxIter int, procs int) (PowerIterResult, error) {
+	if damping < 0 || damping >= 1 {
+		return PowerIterResult{}, errors.New("damping must be in [0, 1)")
+	}
+	if g.NumVertices() == 0 {
+		return PowerIterResult{Scores: []float64{}, Converged: true}, nil
+	}
+	t, err := probabilityVector(teleport, g.NumVertices(), "teleport")
+	if err != nil {
+		return PowerIterResult{}, err
+	}
+	return pageRank(g, t, damping, tol, maxIter, procs)
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// solveLinear solves a x = b by Gaussian elimination with partial pivoting, overwriting a and b.
+func solveLinear(a [][]float64, b []float64) []float64 {
+	n := len(b)
+	for c := 0; c < n; c++ {
+		p := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[p][c]) {
+				p = r
+			}
+		}
+		a[c], a[p] = a[p], a[c]
+		b[c], b[p] = b[p], b[c]
+		for r := c + 1; r < n; r++ {
+			f := a[r][c] / a[c][c]
+			for k := c; k < n; k++ {
+				a[r][k] -= f * a[c][k]
+			}
+			b[r] -= f * b[c]
+		}
+	}
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		sum := b[r]
+		for k := r + 1; k < n; k++ {
+			sum -= a[r][k] * x[k]
+		}
+		x[r] = sum / a[r][r]
+	}
+	return x
+}
+
+// solvePageRank computes personalized PageRank with teleport probabilities t by solving
+// (I - damping M) x = (1 - damping) t, where M is the transition matrix of the random walk
+// with dangling vertices jumping according to t.
+func solvePageRank(g Graph, t []float64, damping float64) []float64 {
+	nv := int(g.NumVertices())
+	a := make([][]float64, nv)
+	b := make([]float64, nv)
+	for v := range a {
+		a[v] = make([]float64, nv)
+		a[v][v] = 1
+		b[v] = (1 - damping) * t[v]
+	}
+	for u := u0; u < uint32(nv); u++ {
+		deg := g.OutDegree(u)
+		if deg == 0 {
+			for v := range a {
+				a[v][u] -= damping * t[v]
+			}
+			continue
+		}
+		for _, v := range g.OutNeighbors(u) {
+			a[v][u] -= damping / float64(deg)
+		}
+	}
+	return solveLinear(a, b)
+}
+
+func TestPageRank(t *testing.T) {
+	cycle, err := NewDiGraph([]uint32{0, 1, 2}, []uint32{1, 2, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := PageRank(cycle, 0.85, 1e-12, 100)
+	if err != nil || !res.Converged {
+		t.Fatalf("PageRank = %v, %v, expected convergence", res, err)
+	}
+	assertScores(t, cycle, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, res.Scores)
+
+	// 1 has no out edges, so x0 = (1 - d) / 2 + d x1 / 2 and x0 + x1 = 1, giving x0 = 1 / (2 + d).
+	g, err := NewDiGraph([]uint32{0}, []uint32{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = PageRank(g, 0.5, 1e-12, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertScores(t, g, []float64{0.4, 0.6}, res.Scores)
+
+	// jumps only to 0.
+	res, err = PersonalizedPageRank(g, []float64{2, 0}, 0.5, 1e-12, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertScores(t, g, []float64{2.0 / 3, 1.0 / 3}, res.Scores)
+
+	res, err = PageRank(g, 0.5, 1e-12, 1)
+	if !errors.Is(err, ErrNotConverged) || res.Converged || res.Iterations != 1 {
+		t.Errorf("PageRank with 1 iteration = %v, %v, expected ErrNotConverged", res, err)
+	}
+	for _, teleport := range [][]float64{{1}, {1, -1}, {0, 0}, {math.NaN(), 1}, {math.Inf(1), 1}} {
+		if _, err := PersonalizedPageRank(g, teleport, 0.5, 1e-12, 100); err == nil {
+			t.Errorf("PersonalizedPageRank accepted the teleport vector %v", teleport)
+		}
+	}
+	for _, damping := range []float64{-0.1, 1} {
+		if _, err := PageRank(g, damping, 1e-12, 100); err == nil {
+			t.Errorf("PageRank accepted a damping of %v", damping)
+		}
+	}
+
+	empty, err := NewDiGraph(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := PageRank(empty, 0.85, 1e-12, 100); err != nil || len(res.Scores) != 0 {
+		t.Errorf("PageRank of an empty graph = %v, %v", res, err)
+	}
+}
+
+func TestPageRankMatchesLinearSolve(t *testing.T) {
+	for _, g := range randomGraphs(t, 19, 20, 40) {
+		nv := g.NumVertices()
+		uniform := make([]float64, nv)
+		teleport := make([]float64, nv)
+		for v := range uniform {
+			uniform[v] = 1 / float64(nv)
+		}
+		teleport[0] = 1
+		want := solvePageRank(g, uniform, 0.85)
+		wantPersonalized := solvePageRank(g, teleport, 0.85)
+		forProcs(func(procs int) {
+			res, err := ParallelPageRank(g, 0.85, 1e-12, 1000, procs)
+			if err != nil {
+				t.Fatalf("%v: ParallelPageRank(procs=%d): %v", g, procs, err)
+			}
+			sum := 0.0
+			for _, x := range res.Scores {
+				sum += x
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Fatalf("%v: ParallelPageRank(procs=%d) scores sum to %v", g, procs, sum)
+			}
+			assertScores(t, g, want, res.Scores)
+
+			res, err = ParallelPersonalizedPageRank(g, teleport, 0.85, 1e-12, 1000, procs)
+			if err != nil {
+				t.Fatalf("%v: ParallelPersonalizedPageRank(procs=%d): %v", g, procs, err)
+			}
+			assertScores(t, g, wantPersonalized, res.Scores)
+		})
+	}
+}
@@ -57,8 +57,18 @@ func (front *Frontier) Write(low, high *uint32, v uint32) {
 	*low++
 }
 
+// blockIter calls fn on blocks of [0, n) using procs goroutines, or directly if procs <= 1.
+func blockIter(n int, procs int, fn func(low, high int)) {
+	if procs <= 1 {
+		fn(0, n)
+		return
+	}
+	async.BlockIter(n, procs, fn)
+}
+
 // processLevel uses Frontiers to dequeue work from currLevel in ReadBlockSize increments.
-func processLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitvec.ABitVec) {
+// If parents is not nil, the parent of each newly visited vertex is recorded.
+func processLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitvec.ABitVec, parents []uint32) {
 	writeLow, writeHigh := u0, u0
 	for {
 		readLow, readHigh := currLevel.NextRead() // if currLevel still has vertices to process, get the indices of a ReadBlockSize block of them
@@ -78,20 +88,25 @@ func processLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitvec.ABitV
 				x1, x2, x3, x4 := visited.GetBuckets4(n1, n2, n3, n4)
 				if visited.TrySetWith(x1, n1) { // if not visited, add to the list of vertices for nextLevel
 					nextLevel.Write(&writeLow, &writeHigh, n1)
+					setParent(parents, n1, v)
 				}
 				if visited.TrySetWith(x2, n2) {
 					nextLevel.Write(&writeLow, &writeHigh, n2)
+					setParent(parents, n2, v)
 				}
 				if visited.TrySetWith(x3, n3) {
 					nextLevel.Write(&writeLow, &writeHigh, n3)
+					setParent(parents, n3, v)
 				}
 				if visited.TrySetWith(x4, n4) {
 					nextLevel.Write(&writeLow, &writeHigh, n4)
+					setParent(parents, n4, v)
 				}
 			}
 			for _, n := range neighbors[i:] { // process any remaining (< 4) neighbors for this vertex
 				if visited.TrySet(n) {
 					nextLevel.Write(&writeLow, &writeHigh, n)
+					setParent(parents, n, v)
 				}
 			}
 		}
@@ -102,28 +117,64 @@ func processLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitvec.ABitV
 	}
 }
 
-// ParallelBFS computes a vector of levels from src in parallel.
-func ParallelBFS(g Graph, src uint32, procs int) {
+// setParent records u as the parent of v if parents is not nil.
+func setParent(parents []uint32, v, u uint32) {
+	if parents != nil {
+		parents[v] = u
+	}
+}
+
+// ParallelBFS computes a vector of levels from src in parallel and returns a vector
+// of vertices visited in order of level along with a vector of levels indexed by vertex,
+// as BFS does. Vertices within a level may be in a different order than in BFS.
+func ParallelBFS(g Graph, src uint32, procs int) (vertexList, vertLevel []uint32) {
+	return parallelBFS(g, []uint32{src}, procs, NoDepthLimit, nil, nil)
+}
+
+// ParallelBFSWithParents computes a vector of levels from src in parallel as ParallelBFS does,
+// and also returns the BFS parent of each vertex. The parent of src and of unreached vertices
+// is NoParent. When a vertex has several neighbors in the previous level, any of them may be
+// chosen as its parent.
+func ParallelBFSWithParents(g Graph, src uint32, procs int) (vertexList, vertLevel, parents []uint32) {
+	parents = make([]uint32, g.NumVertices())
+	for i := range parents {
+		parents[i] = NoParent
+	}
+	vertexList, vertLevel = parallelBFS(g, []uint32{src}, procs, NoDepthLimit, parents, nil)
+	return vertexList, vertLevel, parents
+}
+
+// parallelBFS computes a vector of levels from the vertices in srcs in parallel, visiting vertices at most
+// maxDepth levels away. If parents is not nil, it records parents. If labels is not nil, each newly visited
+// vertex is given the label of its parent, which requires parents.
+func parallelBFS(g Graph, srcs []uint32, procs int, maxDepth uint32, parents, labels []uint32) (vertexList, vertLevel []uint32) {
 	N := g.NumVertices()
-	vertLevel := make([]uint32, N)
+	vertLevel = make([]uint32, N)
+	for i := range vertLevel {
+		vertLevel[i] = unvisited
+	}
 	visited := bitvec.NewABitVec(N)
 
 	maxSize := N + (MaxBlockSize * uint32(procs))
 	currLevel := &Frontier{make([]uint32, 0, maxSize), 0}
 	nextLevel := &Frontier{make([]uint32, maxSize), 0}
 
-	currentLevel := uint32(2)
-	vertLevel[src] = 0
-	visited.TrySet(src)
-
-	currLevel.Data = append(currLevel.Data, src)
+	currentLevel := uint32(1)
+	vertexList = make([]uint32, 0, N)
+	for _, src := range srcs {
+		if visited.TrySet(src) {
+			vertLevel[src] = 0
+			vertexList = append(vertexList, src)
+			currLevel.Data = append(currLevel.Data, src)
+		}
+	}
 
 	wait := make(chan struct{})
-	for len(currLevel.Data) > 0 { // while we have vertices in currentLevel
+	for len(currLevel.Data) > 0 && currentLevel <= maxDepth { // while we have vertices in currentLevel
 
 		async.Spawn(procs, func(i int) { // spawn `procs` goroutines to process vertices in this level,
 			runtime.LockOSThread() // using currLevel as the work queue. Make sure only one goroutine per thread.
-			processLevel(g, currLevel, nextLevel, &visited)
+			processLevel(g, currLevel, nextLevel, &visited, parents)
 		}, func() { wait <- struct{}{} })
 
 		<-wait // this is equivalent to using a WaitGroup but uses a single channel message instead.
@@ -131,22 +182,27 @@ func ParallelBFS(g Graph, src uint32, procs int) {
 		nextLevel.Data = nextLevel.Data[:nextLevel.Head] // "truncate" nextLevel.Data to just the valid data...
 		// ... we need to do this because Frontier.ReadNext uses `len`.
 
-		sentinelCount := u0
 		// now sort nextLevel by block. After this, all data within a given block will be sorted. This ensures that
 		// "most" data are ordered, which preserves some linearity in cache access, but this might not be significant.
 		// More testing is needed.
 		async.BlockIter(int(nextLevel.Head), procs, func(low, high int) {
 			zuint32.SortBYOB(nextLevel.Data[low:high], currLevel.Data[low:high])
-			for index, v := range nextLevel.Data[low:high] {
+			for _, v := range nextLevel.Data[low:high] {
 				if v == EmptySentinel {
-					atomic.AddUint32(&sentinelCount, uint32(high-low-index))
 					break
 				}
 				vertLevel[v] = currentLevel
+				if labels != nil {
+					labels[v] = labels[parents[v]]
+				}
 			}
 		})
 
-		// fmt.Printf("completed level %d, size = %d\n", currentLevel-1, len(nextLevel.Data)-int(sentinelCount))
+		for _, v := range nextLevel.Data {
+			if v != EmptySentinel {
+				vertexList = append(vertexList, v)
+			}
+		}
 
 		currentLevel++
 		currLevel, nextLevel = nextLevel, currLevel
@@ -155,4 +211,5 @@ func ParallelBFS(g Graph, src uint32, procs int) {
 		nextLevel.Data = nextLevel.Data[:maxSize:maxSize] // resize the buffer to `maxSize` elements. We don't care what's in it, because...
 		nextLevel.Head = 0                                // ... we start writing to index 0.
 	}
+	return vertexList, vertLevel
 }
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"sort"
+	"testing"
+)
+
+// levelSets returns the sorted vertices of each level of vertexList, which must be in order of
+// level.
+func levelSets(vertexList, vertLevel []uint32) [][]uint32 {
+	sets := [][]uint32{}
+	for _, v := range vertexList {
+		l := int(vertLevel[v])
+		for len(sets) <= l {
+			sets = append(sets, []uint32{})
+		}
+		if l != len(sets)-1 {
+			return nil // out of order
+		}
+		sets[l] = append(sets[l], v)
+	}
+	for _, s := range sets {
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	}
+	return sets
+}
+
+// bfsTestGraph returns the undirected graph with edges 0-1, 0-2, 1-3, 2-3 and 3-4 and an isolated
+// vertex 5, and the vertices of each level and the level of each vertex from 0.
+func bfsTestGraph(t *testing.T) (g SimpleGraph, sets [][]uint32, levels []uint32) {
+	g, err := New([]uint32{0, 0, 1, 2, 3, 5}, []uint32{1, 2, 3, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, [][]uint32{{0}, {1, 2}, {3}, {4}}, []uint32{0, 1, 1, 2, 3, unvisited}
+}
+
+func TestParallelBFS(t *testing.T) {
+	g, wantSets, wantLevel := bfsTestGraph(t)
+	list, level := BFS(g, 0)
+	checkSame(t, g, "BFS levels", 1, level, wantLevel)
+	checkSame(t, g, "BFS vertices", 1, levelSets(list, level), wantSets)
+	forProcs(func(procs int) {
+		list, level := ParallelBFS(g, 0, procs)
+		checkSame(t, g, "ParallelBFS levels", procs, level, wantLevel)
+		checkSame(t, g, "ParallelBFS vertices", procs, levelSets(list, level), wantSets)
+		_, _, parents := ParallelBFSWithParents(g, 0, procs)
+		if parents[3] != 1 && parents[3] != 2 {
+			t.Fatalf("ParallelBFSWithParents(procs=%d) parents = %v, expected 1 or 2 for 3", procs, parents)
+		}
+		checkSame(t, g, "ParallelBFSWithParents parents", procs, []uint32{parents[0], parents[1], parents[2], parents[4], parents[5]}, []uint32{NoParent, 0, 0, 3, NoParent})
+	})
+}
+
+func TestParallelBFSMatchesBFS(t *testing.T) {
+	for _, g := range parallelTestGraphs(t, 6) {
+		wantList, wantLevel := BFS(g, 0)
+		wantSets := levelSets(wantList, wantLevel)
+		forProcs(func(procs int) {
+			list, level := ParallelBFS(g, 0, procs)
+			checkSame(t, g, "ParallelBFS levels", procs, level, wantLevel)
+			checkSame(t, g, "ParallelBFS vertices", procs, levelSets(list, level), wantSets)
+
+			list, level, parents := ParallelBFSWithParents(g, 0, procs)
+			checkSame(t, g, "ParallelBFSWithParents levels", procs, level, wantLevel)
+			checkSame(t, g, "ParallelBFSWithParents vertices", procs, levelSets(list, level), wantSets)
+			for v, p := range parents {
+				switch {
+				case v == 0 || level[v] == unvisited:
+					if p != NoParent {
+						t.Fatalf("%v: parent of %d is %d, expected NoParent", g, v, p)
+					}
+				case p == NoParent || level[p]+1 != level[v] || !g.HasEdge(p, uint32(v)):
+					t.Fatalf("%v: parent of %d at level %d is %d, which is not a neighbor one level up", g, v, level[v], p)
+				}
+			}
+		})
+	}
+}
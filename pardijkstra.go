@@ -1,17 +1,60 @@
 package graph
 
 import (
-	"fmt"
+	"container/heap"
+	"math"
 	"runtime"
 	"sync/atomic"
 
 	"github.com/egonelbre/async"
-	"github.com/sbromberger/bitvec"
-	"github.com/shawnsmithdev/zermelo/zuint32"
 )
 
-// processDijkstraLevel uses Frontiers to dequeue work from currLevel in ReadBlockSize increments.
-func processDijkstraLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitvec.ABitVec, dists []float32, parents []uint32, pathcounts []uint32) {
+// deltaSampleVertices is the number of vertices whose edges are sampled to choose a bucket width.
+const deltaSampleVertices = 1024
+
+// bucketHeap is a min-heap of bucket indices.
+type bucketHeap []uint64
+
+func (h bucketHeap) Len() int            { return len(h) }
+func (h bucketHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h bucketHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *bucketHeap) Push(x interface{}) { *h = append(*h, x.(uint64)) }
+func (h *bucketHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// bucketOf returns the index of the bucket of width delta holding distance d.
+func bucketOf(d, delta float32) uint64 {
+	return uint64(d / delta)
+}
+
+// loadDist atomically loads a distance stored as float32 bits.
+func loadDist(dists []uint32, v uint32) float32 {
+	return math.Float32frombits(atomic.LoadUint32(&dists[v]))
+}
+
+// tryLowerDist atomically lowers the distance of v to d and returns true if d is lower than the current
+// distance. Non-negative float32s order the same way as their bits, so distances are compared as uint32s.
+func tryLowerDist(dists []uint32, v uint32, d float32) bool {
+	bits := math.Float32bits(d)
+	for {
+		old := atomic.LoadUint32(&dists[v])
+		if bits >= old {
+			return false
+		}
+		if atomic.CompareAndSwapUint32(&dists[v], old, bits) {
+			return true
+		}
+	}
+}
+
+// processBucket uses Frontiers to dequeue work from currLevel in ReadBlockSize increments and relaxes the
+// out edges of each vertex. Vertices whose distance improves are written to nextLevel if they remain in
+// bucket k, and appended to far otherwise. queued ensures a vertex is written to nextLevel once per round.
+func processBucket(g Graph, w weigher, currLevel, nextLevel *Frontier, dists, queued []uint32, round uint32, k uint64, delta float32, far *[]uint32) {
 	writeLow, writeHigh := u0, u0
 	for {
 		readLow, readHigh := currLevel.NextRead() // if currLevel still has vertices to process, get the indices of a ReadBlockSize block of them
@@ -23,92 +66,17 @@ func processDijkstraLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitv
 			if u == EmptySentinel { // if we hit a sentinel within the block, skip it
 				continue
 			}
-			alt := min(maxDist, dists[u]+1)
-			vs := g.OutNeighbors(u) // get the outNeighbors of the vertex under inspection
-			// i := 0
-			for _, v := range vs {
-				// for ; i < len(vs)-3; i += 4 { // unroll loop for visited
-				// 	v1, v2, v3, v4 := vs[i], vs[i+1], vs[i+2], vs[i+3]
-				// 	x1, x2, x3, x4 := visited.GetBuckets4(v1, v2, v3, v4)
-				// 	if visited.TrySetWith(x1, v1) { // if not visited, add to the list of vertices for nextLevel
-				// 		nextLevel.Write(&writeLow, &writeHigh, v1)
-				// 		dists[v1] = alt
-				// 		parents[v1] = u
-				// 		pathcounts[v1] += pathcounts[u]
-				// 	} else {
-				// 		if alt < dists[v1] {
-				// 			dists[v1] = alt
-				// 			parents[v1] = u
-				// 			pathcounts[v1] = 0
-				// 		}
-				// 		if alt == dists[v1] {
-				// 			pathcounts[v1] += pathcounts[u]
-				// 		}
-				// 	}
-				// 	if visited.TrySetWith(x2, v2) {
-				// 		nextLevel.Write(&writeLow, &writeHigh, v2)
-				// 		dists[v2] = alt
-				// 		dists[v2] = alt
-				// 		parents[v2] = u
-				// 		pathcounts[v2] += pathcounts[u]
-				// 	} else {
-				// 		if alt < dists[v2] {
-				// 			dists[v2] = alt
-				// 			parents[v2] = u
-				// 			pathcounts[v2] = 0
-				// 		}
-				// 		if alt == dists[v2] {
-				// 			pathcounts[v2] += pathcounts[u]
-				// 		}
-				// 	}
-				// 	if visited.TrySetWith(x3, v3) {
-				// 		nextLevel.Write(&writeLow, &writeHigh, v3)
-				// 		dists[v3] = alt
-				// 		dists[v3] = alt
-				// 		parents[v3] = u
-				// 		pathcounts[v3] += pathcounts[u]
-				// 	} else {
-				// 		if alt < dists[v3] {
-				// 			dists[v3] = alt
-				// 			parents[v3] = u
-				// 			pathcounts[v3] = 0
-				// 		}
-				// 		if alt == dists[v3] {
-				// 			pathcounts[v3] += pathcounts[u]
-				// 		}
-				// 	}
-				// 	if visited.TrySetWith(x4, v4) {
-				// 		nextLevel.Write(&writeLow, &writeHigh, v4)
-				// 		dists[v4] = alt
-				// 		dists[v4] = alt
-				// 		parents[v4] = u
-				// 		pathcounts[v4] += pathcounts[u]
-				// 	} else {
-				// 		if alt < dists[v4] {
-				// 			dists[v4] = alt
-				// 			parents[v4] = u
-				// 			pathcounts[v4] = 0
-				// 		}
-				// 		if alt == dists[v4] {
-				// 			pathcounts[v4] += pathcounts[u]
-				// 		}
-				// 	}
-				// }
-				// for _, v := range vs[i:] { // process any remaining (< 4) neighbors for this vertex
-				if visited.TrySet(v) {
+			du := loadDist(dists, u)
+			vs, ws := w.outEdges(g, u)
+			for i, v := range vs {
+				alt := min(maxDist, du+w.weight(u, v, ws, i))
+				if !tryLowerDist(dists, v, alt) {
+					continue
+				}
+				if bucketOf(alt, delta) != k {
+					*far = append(*far, v)
+				} else if atomic.SwapUint32(&queued[v], round) != round {
 					nextLevel.Write(&writeLow, &writeHigh, v)
-					dists[v] = alt
-					parents[v] = u
-					atomic.AddUint32(&pathcounts[v], pathcounts[u])
-				} else {
-					if alt < dists[v] {
-						dists[v] = alt
-						parents[v] = u
-						pathcounts[v] = 0
-					}
-					if alt == dists[v] {
-						atomic.AddUint32(&pathcounts[v], pathcounts[u])
-					}
 				}
 			}
 		}
@@ -119,74 +87,122 @@ func processDijkstraLevel(g Graph, currLevel, nextLevel *Frontier, visited *bitv
 	}
 }
 
-// ParallelDijkstra computes a vector of levels from src in parallel.
-func ParallelDijkstra(g Graph, src uint32, procs int) DijkstraState {
+// DeltaStepping computes the DijkstraState returned by Dijkstra with the delta-stepping algorithm,
+// relaxing buckets of vertices of width `delta` > 0 with `procs` goroutines.
+func DeltaStepping(g Graph, src uint32, weightFn func(uint32, uint32) float32, delta float32, withPreds bool, procs int) DijkstraState {
+	w := newWeigher(g, weightFn)
 	N := g.NumVertices()
-	vertLevel := make([]uint32, N)
-	visited := bitvec.NewABitVec(N)
 
 	maxSize := N + MaxBlockSize*uint32(procs)
 	currLevel := &Frontier{make([]uint32, 0, maxSize), 0}
 	nextLevel := &Frontier{make([]uint32, maxSize), 0}
 
-	currentLevel := uint32(2)
-	parents := make([]uint32, N)
-	pathcounts := make([]uint32, N)
-	dists := make([]float32, N)
-
-	for i := range dists {
-		dists[i] = maxDist
+	distBits := make([]uint32, N)
+	queued := make([]uint32, N)
+	for i := range distBits {
+		distBits[i] = math.Float32bits(maxDist)
 	}
+	distBits[src] = 0
 
-	vertLevel[src] = 0
-	dists[src] = 0
-	parents[src] = src
-	pathcounts[src] = 1
-	visited.TrySet(src)
+	buckets := make(map[uint64][]uint32)
+	bucketIndices := &bucketHeap{}
+	far := make([][]uint32, procs)
 
+	k := uint64(0)
+	round := uint32(0)
 	currLevel.Data = append(currLevel.Data, src)
 
 	wait := make(chan struct{})
-	for len(currLevel.Data) > 0 { // while we have vertices in currentLevel
-
-		async.Spawn(procs, func(i int) { // spawn `procs` goroutines to process vertices in this level,
-			runtime.LockOSThread() // using currLevel as the work queue. Make sure only one goroutine per thread.
-			processDijkstraLevel(g, currLevel, nextLevel, &visited, dists, parents, pathcounts)
-		}, func() { wait <- struct{}{} })
-
-		<-wait // this is equivalent to using a WaitGroup but uses a single channel message instead.
-
-		nextLevel.Data = nextLevel.Data[:nextLevel.Head] // "truncate" nextLevel.Data to just the valid data...
-		// ... we need to do this because Frontier.ReadNext uses `len`.
-
-		sentinelCount := u0
-		// now sort nextLevel by block. After this, all data within a given block will be sorted. This ensures that
-		// "most" data are ordered, which preserves some linearity in cache access, but this might not be significant.
-		// More testing is needed.
-		async.BlockIter(int(nextLevel.Head), procs, func(low, high int) {
-			zuint32.SortBYOB(nextLevel.Data[low:high], currLevel.Data[low:high])
-			for index, v := range nextLevel.Data[low:high] {
-				if v == EmptySentinel {
-					atomic.AddUint32(&sentinelCount, uint32(high-low-index))
-					break
+	for {
+		for len(currLevel.Data) > 0 { // while we have vertices in the current bucket
+			round++
+			async.Spawn(procs, func(i int) { // spawn `procs` goroutines to process vertices in this bucket,
+				runtime.LockOSThread() // using currLevel as the work queue. Make sure only one goroutine per thread.
+				processBucket(g, w, currLevel, nextLevel, distBits, queued, round, k, delta, &far[i])
+			}, func() { wait <- struct{}{} })
+
+			<-wait // this is equivalent to using a WaitGroup but uses a single channel message instead.
+
+			nextLevel.Data = nextLevel.Data[:nextLevel.Head] // "truncate" nextLevel.Data to just the valid data...
+			// ... we need to do this because Frontier.ReadNext uses `len`.
+
+			currLevel, nextLevel = nextLevel, currLevel
+			currLevel.Head = 0 // start reading from 0
+			// reset buffer for next round
+			nextLevel.Data = nextLevel.Data[:maxSize:maxSize] // resize the buffer to `maxSize` elements. We don't care what's in it, because...
+			nextLevel.Head = 0                                // ... we start writing to index 0.
+		}
+
+		// move vertices relaxed into later buckets to their buckets.
+		for i := range far {
+			for _, v := range far[i] {
+				b := bucketOf(loadDist(distBits, v), delta)
+				if b <= k { // already settled in this bucket
+					continue
+				}
+				if _, ok := buckets[b]; !ok {
+					heap.Push(bucketIndices, b)
 				}
-				vertLevel[v] = currentLevel
+				buckets[b] = append(buckets[b], v)
 			}
-		})
+			far[i] = far[i][:0]
+		}
 
-		fmt.Printf("completed level %d, size = %d\n", currentLevel-1, len(nextLevel.Data)-int(sentinelCount))
+		// find the next non-empty bucket. A vertex may appear in several buckets as its distance
+		// decreases; it belongs only to the bucket of its current distance.
+		currLevel.Data = currLevel.Data[:0]
+		round++
+		for len(currLevel.Data) == 0 && bucketIndices.Len() > 0 {
+			k = heap.Pop(bucketIndices).(uint64)
+			for _, v := range buckets[k] {
+				if bucketOf(loadDist(distBits, v), delta) == k && queued[v] != round {
+					queued[v] = round
+					currLevel.Data = append(currLevel.Data, v)
+				}
+			}
+			delete(buckets, k)
+		}
+		if len(currLevel.Data) == 0 {
+			break
+		}
+	}
 
-		currentLevel++
-		currLevel, nextLevel = nextLevel, currLevel
-		currLevel.Head = 0 // start reading from 0
-		// reset buffer for next level
-		nextLevel.Data = nextLevel.Data[:maxSize:maxSize] // resize the buffer to `maxSize` elements. We don't care what's in it, because...
-		nextLevel.Head = 0                                // ... we start writing to index 0.
+	dists := make([]float32, N)
+	for i, d := range distBits {
+		dists[i] = math.Float32frombits(d)
 	}
+	parents, pathcounts, preds := shortestPathTree(g, w, src, dists, withPreds, procs)
 	ds := DijkstraState{
-		Parents:    parents,
-		Dists:      dists,
-		Pathcounts: pathcounts,
+		Parents:      parents,
+		Dists:        dists,
+		Pathcounts:   pathcounts,
+		Predecessors: preds,
 	}
 	return ds
 }
+
+// ParallelDijkstra calls DeltaStepping with a `delta` equal to the mean weight of a sample of edges.
+func ParallelDijkstra(g Graph, src uint32, weightFn func(uint32, uint32) float32, withPreds bool, procs int) DijkstraState {
+	return DeltaStepping(g, src, weightFn, sampleDelta(g, newWeigher(g, weightFn)), withPreds, procs)
+}
+
+// sampleDelta returns the mean weight of the out edges of up to deltaSampleVertices evenly spaced
+// vertices, or 1 if there are no such edges or their weights are all zero.
+func sampleDelta(g Graph, w weigher) float32 {
+	nv := g.NumVertices()
+	step := uint64(nv/deltaSampleVertices + 1)
+	sum := float64(0)
+	n := 0
+	for u64 := uint64(0); u64 < uint64(nv); u64 += step {
+		u := uint32(u64)
+		vs, ws := w.outEdges(g, u)
+		for i, v := range vs {
+			sum += float64(w.weight(u, v, ws, i))
+			n++
+		}
+	}
+	if n == 0 || sum <= 0 {
+		return 1
+	}
+	return float32(sum / float64(n))
+}
@@ -0,0 +1,34 @@
+package graph
+
+import "testing"
+
+func TestDeltaStepping(t *testing.T) {
+	g, err := NewWeightedDiGraph([]uint32{0, 0, 2, 1, 2}, []uint32{1, 2, 1, 3, 3}, []float32{4, 1, 2, 1, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DijkstraState{
+		Parents:      []uint32{NoParent, 2, 0, 1},
+		Dists:        []float32{0, 3, 1, 4},
+		Pathcounts:   []uint32{1, 1, 1, 1},
+		Predecessors: [][]uint32{nil, {2}, {0}, {1}},
+	}
+	forProcs(func(procs int) {
+		for _, delta := range []float32{0.5, 3, 100} {
+			checkSame(t, g, "DeltaStepping", procs, DeltaStepping(g, 0, nil, delta, true, procs), want)
+		}
+		checkSame(t, g, "ParallelDijkstra", procs, ParallelDijkstra(g, 0, nil, true, procs), want)
+	})
+}
+
+func TestDeltaSteppingMatchesDijkstra(t *testing.T) {
+	for _, g := range parallelTestGraphs(t, 5) {
+		want := Dijkstra(g, 0, nil, true)
+		forProcs(func(procs int) {
+			for _, delta := range []float32{0.5, 3, 100} {
+				checkSame(t, g, "DeltaStepping", procs, DeltaStepping(g, 0, nil, delta, true, procs), want)
+			}
+			checkSame(t, g, "ParallelDijkstra", procs, ParallelDijkstra(g, 0, nil, true, procs), want)
+		})
+	}
+}
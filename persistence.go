@@ -1,17 +1,33 @@
 package graph
 
 import (
+	"fmt"
 	"os"
 	"unsafe"
 
 	mmap "github.com/edsrzf/mmap-go"
+	"github.com/sbromberger/graphmatrix"
 )
 
-// raw defines a struct for binary SimpleGraphs format.
+// rawMagic starts files that have a flags word. Files written before the flags word was added
+// start with the length of a vector, which is never this large.
+const rawMagic = uint64(0x7367726170686773)
+
+// Flags stored in the header of a raw file.
+const (
+	rawDirected = uint64(1) << iota
+	rawWeighted
+)
+
+// raw defines a struct for binary SimpleGraphs format. Weighted graphs store their
+// edge weights in an optional section following the matrices.
 type raw struct {
 	file *os.File
 	data mmap.MMap
 
+	HasFlags bool
+	Flags    uint64
+
 	FIndicesLen uint64
 	FIndPtrLen  uint64
 	BIndicesLen uint64
@@ -22,6 +38,12 @@ type raw struct {
 
 	BIndPtr  []uint64
 	BIndices []uint32
+
+	FWeightsLen uint64
+	BWeightsLen uint64
+
+	FWeights []float32
+	BWeights []float32
 }
 
 func (raw *raw) close() error {
@@ -38,6 +60,31 @@ func (raw *raw) close() error {
 	return err2
 }
 
+// rawUint64s returns the n uint64s at offset x of data without copying them. An empty section
+// may start at the end of data, so it is returned as nil rather than indexed.
+func rawUint64s(data []byte, x int, n uint64) []uint64 {
+	if n == 0 {
+		return nil
+	}
+	return ((*[1 << 40]uint64)(unsafe.Pointer(&data[x])))[0:int(n)]
+}
+
+// rawUint32s returns the n uint32s at offset x of data, as rawUint64s does.
+func rawUint32s(data []byte, x int, n uint64) []uint32 {
+	if n == 0 {
+		return nil
+	}
+	return ((*[1 << 40]uint32)(unsafe.Pointer(&data[x])))[0:int(n)]
+}
+
+// rawFloat32s returns the n float32s at offset x of data, as rawUint64s does.
+func rawFloat32s(data []byte, x int, n uint64) []float32 {
+	if n == 0 {
+		return nil
+	}
+	return ((*[1 << 40]float32)(unsafe.Pointer(&data[x])))[0:int(n)]
+}
+
 func loadRaw(filename string) (*raw, error) {
 	var err error
 	raw := &raw{}
@@ -55,6 +102,23 @@ func loadRaw(filename string) (*raw, error) {
 	}
 
 	x := 0
+	var magic uint64
+	if len(raw.data) < 8 {
+		raw.close()
+		return nil, fmt.Errorf("%s is too short to hold a graph", filename)
+	}
+	copy((*[8]byte)(unsafe.Pointer(&magic))[:], raw.data[x:x+8])
+	if magic == rawMagic {
+		raw.HasFlags = true
+		x += 16
+	}
+	if x+32 > len(raw.data) {
+		raw.close()
+		return nil, fmt.Errorf("%s is too short to hold a graph", filename)
+	}
+	if raw.HasFlags {
+		copy((*[8]byte)(unsafe.Pointer(&raw.Flags))[:], raw.data[8:16])
+	}
 	copy((*[8]byte)(unsafe.Pointer(&raw.FIndPtrLen))[:], raw.data[x:x+8])
 	x += 8
 	copy((*[8]byte)(unsafe.Pointer(&raw.FIndicesLen))[:], raw.data[x:x+8])
@@ -65,24 +129,114 @@ func loadRaw(filename string) (*raw, error) {
 	copy((*[8]byte)(unsafe.Pointer(&raw.BIndicesLen))[:], raw.data[x:x+8])
 	x += 8
 
-	raw.FIndPtr = ((*[1 << 40]uint64)(unsafe.Pointer(&raw.data[x])))[0:int(raw.FIndPtrLen)]
+	if uint64(x)+8*(raw.FIndPtrLen+raw.BIndPtrLen)+4*(raw.FIndicesLen+raw.BIndicesLen) > uint64(len(raw.data)) {
+		raw.close()
+		return nil, fmt.Errorf("%s is truncated", filename)
+	}
+	raw.FIndPtr = rawUint64s(raw.data, x, raw.FIndPtrLen)
 	x += 8 * int(raw.FIndPtrLen)
-	raw.FIndices = ((*[1 << 40]uint32)(unsafe.Pointer(&raw.data[x])))[0:int(raw.FIndicesLen)]
+	raw.FIndices = rawUint32s(raw.data, x, raw.FIndicesLen)
 	x += 4 * int(raw.FIndicesLen)
 
-	raw.BIndPtr = ((*[1 << 40]uint64)(unsafe.Pointer(&raw.data[x])))[0:int(raw.BIndPtrLen)]
+	raw.BIndPtr = rawUint64s(raw.data, x, raw.BIndPtrLen)
 	x += 8 * int(raw.BIndPtrLen)
-	raw.BIndices = ((*[1 << 40]uint32)(unsafe.Pointer(&raw.data[x])))[0:int(raw.BIndicesLen)]
+	raw.BIndices = rawUint32s(raw.data, x, raw.BIndicesLen)
+	x += 4 * int(raw.BIndicesLen)
+
+	if x+16 > len(raw.data) { // no edge weights
+		return raw, nil
+	}
+	copy((*[8]byte)(unsafe.Pointer(&raw.FWeightsLen))[:], raw.data[x:x+8])
+	x += 8
+	copy((*[8]byte)(unsafe.Pointer(&raw.BWeightsLen))[:], raw.data[x:x+8])
+	x += 8
+
+	if uint64(x)+4*(raw.FWeightsLen+raw.BWeightsLen) > uint64(len(raw.data)) {
+		raw.close()
+		return nil, fmt.Errorf("%s is truncated", filename)
+	}
+	raw.FWeights = rawFloat32s(raw.data, x, raw.FWeightsLen)
+	x += 4 * int(raw.FWeightsLen)
+	raw.BWeights = rawFloat32s(raw.data, x, raw.BWeightsLen)
 
 	return raw, nil
 }
 
+// check returns an error if the file `fn` read into raw holds a graph of a different kind.
+// Files without flags cannot be told apart, but an undirected graph must have equal matrices.
+func (raw *raw) check(fn string, directed, weighted bool) error {
+	if !raw.HasFlags {
+		if !directed && !equalRawMatrices(raw) {
+			return fmt.Errorf("%s does not contain an undirected graph", fn)
+		}
+		return nil
+	}
+	if isDirected := raw.Flags&rawDirected != 0; isDirected != directed {
+		if isDirected {
+			return fmt.Errorf("%s contains a directed graph", fn)
+		}
+		return fmt.Errorf("%s contains an undirected graph", fn)
+	}
+	if isWeighted := raw.Flags&rawWeighted != 0; isWeighted != weighted {
+		if isWeighted {
+			return fmt.Errorf("%s contains a weighted graph", fn)
+		}
+		return fmt.Errorf("%s does not contain edge weights", fn)
+	}
+	return nil
+}
+
+// equalRawMatrices returns true if the forward and backward matrices of raw are equal.
+func equalRawMatrices(raw *raw) bool {
+	if len(raw.FIndPtr) != len(raw.BIndPtr) || len(raw.FIndices) != len(raw.BIndices) {
+		return false
+	}
+	for i := range raw.FIndPtr {
+		if raw.FIndPtr[i] != raw.BIndPtr[i] {
+			return false
+		}
+	}
+	for i := range raw.FIndices {
+		if raw.FIndices[i] != raw.BIndices[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Save saves a SimpleGraph to a file in raw (binary) format.
 func (g SimpleGraph) Save(filename string) error {
-	FIndPtr := g.FMat().IndPtr
-	FIndices := g.FMat().Indices
-	BIndPtr := g.BMat().IndPtr
-	BIndices := g.BMat().Indices
+	return saveRaw(filename, 0, g.FMat(), g.BMat())
+}
+
+// Save saves a SimpleDiGraph to a file in raw (binary) format.
+func (g SimpleDiGraph) Save(filename string) error {
+	return saveRaw(filename, rawDirected, g.FMat(), g.BMat())
+}
+
+// Save saves a SimpleWeightedGraph to a file in raw (binary) format.
+func (g SimpleWeightedGraph) Save(filename string) error {
+	return saveRawWeighted(filename, rawWeighted, g.FMat(), g.BMat(), g.fws, g.bws)
+}
+
+// Save saves a SimpleWeightedDiGraph to a file in raw (binary) format.
+func (g SimpleWeightedDiGraph) Save(filename string) error {
+	return saveRawWeighted(filename, rawDirected|rawWeighted, g.FMat(), g.BMat(), g.fws, g.bws)
+}
+
+// saveRaw saves forward and backward matrices to a file in raw (binary) format, with the
+// header flags `flags`.
+func saveRaw(filename string, flags uint64, fmx, bmx graphmatrix.GraphMatrix) error {
+	return saveRawWeighted(filename, flags, fmx, bmx, nil, nil)
+}
+
+// saveRawWeighted saves forward and backward matrices to a file in raw (binary) format, with the
+// header flags `flags`. If fws is not nil, the edge weights fws and bws are saved after the matrices.
+func saveRawWeighted(filename string, flags uint64, fmx, bmx graphmatrix.GraphMatrix, fws, bws []float32) error {
+	FIndPtr := fmx.IndPtr
+	FIndices := fmx.Indices
+	BIndPtr := bmx.IndPtr
+	BIndices := bmx.Indices
 
 	output, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
@@ -100,7 +254,16 @@ func (g SimpleGraph) Save(filename string) error {
 	BIndPtrBytes := 8 * len(BIndPtr)
 	BIndicesBytes := 4 * len(BIndices)
 
-	err = output.Truncate(int64(8 + 8 + 8 + 8 + FIndPtrBytes + FIndicesBytes + BIndPtrBytes + BIndicesBytes))
+	FWeightsLen := int64(len(fws))
+	BWeightsLen := int64(len(bws))
+	FWeightsBytes := 4 * len(fws)
+	BWeightsBytes := 4 * len(bws)
+	weightsBytes := 0
+	if fws != nil {
+		weightsBytes = 8 + 8 + FWeightsBytes + BWeightsBytes
+	}
+
+	err = output.Truncate(int64(8 + 8 + 8 + 8 + 8 + 8 + FIndPtrBytes + FIndicesBytes + BIndPtrBytes + BIndicesBytes + weightsBytes))
 	if err != nil {
 		return err
 	}
@@ -113,6 +276,12 @@ func (g SimpleGraph) Save(filename string) error {
 
 	x := 0
 
+	magic := rawMagic
+	copy(data[x:x+8], ((*[8]byte)(unsafe.Pointer(&magic))[:]))
+	x += 8
+	copy(data[x:x+8], ((*[8]byte)(unsafe.Pointer(&flags))[:]))
+	x += 8
+
 	copy(data[x:x+8], ((*[8]byte)(unsafe.Pointer(&FIndPtrLen))[:]))
 	x += 8
 	copy(data[x:x+8], ((*[8]byte)(unsafe.Pointer(&FIndicesLen))[:]))
@@ -134,7 +303,7 @@ func (g SimpleGraph) Save(filename string) error {
 		x += FIndicesBytes
 	}
 
-	if len(FIndPtr) > 0 {
+	if len(BIndPtr) > 0 {
 		copy(data[x:x+BIndPtrBytes],
 			((*[1 << 40]byte)(unsafe.Pointer(&BIndPtr[0]))[:BIndPtrBytes]))
 		x += BIndPtrBytes
@@ -145,23 +314,115 @@ func (g SimpleGraph) Save(filename string) error {
 		x += BIndicesBytes
 	}
 
+	if fws == nil {
+		return nil
+	}
+
+	copy(data[x:x+8], ((*[8]byte)(unsafe.Pointer(&FWeightsLen))[:]))
+	x += 8
+	copy(data[x:x+8], ((*[8]byte)(unsafe.Pointer(&BWeightsLen))[:]))
+	x += 8
+
+	if len(fws) > 0 {
+		copy(data[x:x+FWeightsBytes],
+			((*[1 << 40]byte)(unsafe.Pointer(&fws[0]))[:FWeightsBytes]))
+		x += FWeightsBytes
+	}
+	if len(bws) > 0 {
+		copy(data[x:x+BWeightsBytes],
+			((*[1 << 40]byte)(unsafe.Pointer(&bws[0]))[:BWeightsBytes]))
+	}
+
 	return nil
 }
 
 // Load loads a raw (binary) SimpleGraph file and returns a SimpleGraph.
 func Load(fn string) (SimpleGraph, error) {
-	raw, err := loadRaw(fn)
+	findptr, find, bindptr, bind, err := loadRawVecs(fn, false)
 	if err != nil {
 		return SimpleGraph{}, err
 	}
+	return FromRaw(findptr, find, bindptr, bind)
+}
+
+// LoadDiGraph loads a raw (binary) SimpleDiGraph file and returns a SimpleDiGraph.
+func LoadDiGraph(fn string) (SimpleDiGraph, error) {
+	findptr, find, bindptr, bind, err := loadRawVecs(fn, true)
+	if err != nil {
+		return SimpleDiGraph{}, err
+	}
+	return DiGraphFromRaw(findptr, find, bindptr, bind)
+}
+
+// LoadWeighted loads a raw (binary) SimpleWeightedGraph file and returns a SimpleWeightedGraph.
+func LoadWeighted(fn string) (SimpleWeightedGraph, error) {
+	findptr, find, fws, bindptr, bind, bws, err := loadRawWeightedVecs(fn, false)
+	if err != nil {
+		return SimpleWeightedGraph{}, err
+	}
+	return WeightedFromRaw(findptr, find, fws, bindptr, bind, bws)
+}
 
-	find := make([]uint32, raw.FIndicesLen)
-	findptr := make([]uint64, raw.FIndPtrLen)
-	bind := make([]uint32, raw.BIndicesLen)
-	bindptr := make([]uint64, raw.BIndPtrLen)
+// LoadWeightedDiGraph loads a raw (binary) SimpleWeightedDiGraph file and returns a SimpleWeightedDiGraph.
+func LoadWeightedDiGraph(fn string) (SimpleWeightedDiGraph, error) {
+	findptr, find, fws, bindptr, bind, bws, err := loadRawWeightedVecs(fn, true)
+	if err != nil {
+		return SimpleWeightedDiGraph{}, err
+	}
+	return WeightedDiGraphFromRaw(findptr, find, fws, bindptr, bind, bws)
+}
+
+// loadRawWeightedVecs reads a raw (binary) file holding a weighted graph, directed if `directed`
+// is true, and returns copies of its matrix vectors and edge weights.
+func loadRawWeightedVecs(fn string, directed bool) (findptr []uint64, find []uint32, fws []float32, bindptr []uint64, bind []uint32, bws []float32, err error) {
+	raw, err := loadRaw(fn)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	defer raw.close()
+
+	if err := raw.check(fn, directed, true); err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	if raw.FWeightsLen != raw.FIndicesLen || raw.BWeightsLen != raw.BIndicesLen {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("%s does not contain edge weights", fn)
+	}
+
+	find = make([]uint32, raw.FIndicesLen)
+	findptr = make([]uint64, raw.FIndPtrLen)
+	fws = make([]float32, raw.FWeightsLen)
+	bind = make([]uint32, raw.BIndicesLen)
+	bindptr = make([]uint64, raw.BIndPtrLen)
+	bws = make([]float32, raw.BWeightsLen)
 	copy(find, raw.FIndices)
 	copy(findptr, raw.FIndPtr)
+	copy(fws, raw.FWeights)
 	copy(bind, raw.BIndices)
 	copy(bindptr, raw.BIndPtr)
-	return FromRaw(findptr, find, bindptr, bind)
+	copy(bws, raw.BWeights)
+	return findptr, find, fws, bindptr, bind, bws, nil
+}
+
+// loadRawVecs reads a raw (binary) file holding an unweighted graph, directed if `directed` is
+// true, and returns copies of its matrix vectors.
+func loadRawVecs(fn string, directed bool) (findptr []uint64, find []uint32, bindptr []uint64, bind []uint32, err error) {
+	raw, err := loadRaw(fn)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer raw.close()
+
+	if err := raw.check(fn, directed, false); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	find = make([]uint32, raw.FIndicesLen)
+	findptr = make([]uint64, raw.FIndPtrLen)
+	bind = make([]uint32, raw.BIndicesLen)
+	bindptr = make([]uint64, raw.BIndPtrLen)
+	copy(find, raw.FIndices)
+	copy(findptr, raw.FIndPtr)
+	copy(bind, raw.BIndices)
+	copy(bindptr, raw.BIndPtr)
+	return findptr, find, bindptr, bind, nil
 }
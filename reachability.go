@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// reachFrame is a component on the DFS path of a labeling traversal, the offset at which the
+// traversal of its children started, and the number of children examined.
+type reachFrame struct {
+	u     uint32
+	start int
+	next  int
+}
+
+// ReachabilityIndex answers reachability queries on a graph using GRAIL interval labels on its
+// condensation. Each labeling comes from a depth-first traversal of the condensation in random
+// order and gives every component its post-order rank and the lowest rank among its
+// descendants; a component can only reach the components whose intervals lie within its own in
+// every labeling. Queries that the labels cannot rule out are answered by a depth-first search
+// that skips the components the labels rule out. A ReachabilityIndex is safe for concurrent
+// queries.
+type ReachabilityIndex struct {
+	labels []uint32 // component of each vertex
+	dag    SimpleDiGraph
+	levels []uint32   // length of the longest path from a source component to each component
+	low    [][]uint32 // lowest post-order rank among the descendants of each component, per labeling
+	post   [][]uint32 // post-order rank of each component, per labeling
+}
+
+// NewReachabilityIndex builds a ReachabilityIndex for g with `labelings` interval labelings
+// drawn with random seed `seed`. More labelings rule out more queries at the cost of memory
+// proportional to NumVertices of the condensation for each; 5 is a good default.
+func NewReachabilityIndex(g Graph, labelings int, seed int64) (ReachabilityIndex, error) {
+	if labelings < 1 {
+		return ReachabilityIndex{}, errors.New("labelings must be positive")
+	}
+	dag, labels, err := Condensation(g)
+	if err != nil {
+		return ReachabilityIndex{}, err
+	}
+	order, err := TopologicalSort(dag)
+	if err != nil {
+		return ReachabilityIndex{}, err
+	}
+	nc := dag.NumVertices()
+	idx := ReachabilityIndex{
+		labels: labels,
+		dag:    dag,
+		levels: make([]uint32, nc),
+		low:    make([][]uint32, labelings),
+		post:   make([][]uint32, labelings),
+	}
+	for _, u := range order {
+		for _, v := range dag.OutNeighbors(u) {
+			if idx.levels[u]+1 > idx.levels[v] {
+				idx.levels[v] = idx.levels[u] + 1
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	frames := []reachFrame{}
+	for i := 0; i < labelings; i++ {
+		low := make([]uint32, nc)
+		post := make([]uint32, nc)
+		for c := range post {
+			post[c] = unvisited
+		}
+		rank := u0
+		for _, root := range r.Perm(int(nc)) {
+			if post[root] != unvisited || dag.InDegree(uint32(root)) > 0 {
+				continue
+			}
+			frames = append(frames, reachFrame{u: uint32(root), start: r.Intn(int(dag.OutDegree(uint32(root))) + 1)})
+			for len(frames) > 0 {
+				top := &frames[len(frames)-1]
+				children := dag.OutNeighbors(top.u)
+				if top.next < len(children) {
+					v := children[(top.start+top.next)%len(children)]
+					top.next++
+					if post[v] == unvisited {
+						frames = append(frames, reachFrame{u: v, start: r.Intn(len(dag.OutNeighbors(v)) + 1)})
+					}
+					continue
+				}
+				// every child has finished, so its interval is final.
+				u := top.u
+				frames = frames[:len(frames)-1]
+				post[u], low[u] = rank, rank
+				rank++
+				for _, v := range children {
+					if low[v] < low[u] {
+						low[u] = low[v]
+					}
+				}
+			}
+		}
+		idx.low[i], idx.post[i] = low, post
+	}
+	return idx, nil
+}
+
+// mayReach returns false if the labels show that component u cannot reach component v.
+func (idx ReachabilityIndex) mayReach(u, v uint32) bool {
+	if idx.levels[u] >= idx.levels[v] {
+		return u == v
+	}
+	for i := range idx.post {
+		if idx.low[i][v] < idx.low[i][u] || idx.post[i][v] > idx.post[i][u] {
+			return false
+		}
+	}
+	return true
+}
+
+// Reachable returns true if there is a path from u to v. Every vertex reaches itself.
+func (idx ReachabilityIndex) Reachable(u, v uint32) bool {
+	cu, cv := idx.labels[u], idx.labels[v]
+	if !idx.mayReach(cu, cv) {
+		return false
+	}
+	if cu == cv {
+		return true
+	}
+	visited := map[uint32]bool{cu: true}
+	stack := []uint32{cu}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, w := range idx.dag.OutNeighbors(c) {
+			if w == cv {
+				return true
+			}
+			if !visited[w] && idx.mayReach(w, cv) {
+				visited[w] = true
+				stack = append(stack, w)
+			}
+		}
+	}
+	return false
+}
+
+// TransitiveClosure returns the transitive closure of g, the graph with an edge from u to v
+// whenever g has a path of one or more edges from u to v; vertices on a cycle have a self loop.
+// Undirected graphs are treated as having edges in both directions. The closure can have up to
+// NumVertices squared edges, so it is meant for small graphs; use a ReachabilityIndex for large
+// ones.
+func TransitiveClosure(g Graph) (SimpleDiGraph, error) {
+	nv := g.NumVertices()
+	// mark[w] == u+1 if w is reachable from u by a path of one or more edges.
+	mark := make([]uint32, nv)
+	stack := []uint32{}
+	ss, ds := []uint32{}, []uint32{}
+	for u := u0; u < nv; u++ {
+		stack = append(stack, u)
+		for len(stack) > 0 {
+			x := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, w := range g.OutNeighbors(x) {
+				if mark[w] != u+1 {
+					mark[w] = u + 1
+					ss = append(ss, u)
+					ds = append(ds, w)
+					stack = append(stack, w)
+				}
+			}
+		}
+	}
+	return newDiGraph(nv, ss, ds)
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestReachabilityIndex(t *testing.T) {
+	// the cycle 0 -> 1 -> 2 -> 0, the edge 2 -> 3 and the isolated vertex 4.
+	g, err := newDiGraph(5, []uint32{0, 1, 2, 2}, []uint32{1, 2, 0, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closure, err := TransitiveClosure(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := NewReachabilityIndex(g, 2, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for u := u0; u < 5; u++ {
+		for v := u0; v < 5; v++ {
+			want := u < 3 && v < 4
+			if closure.HasEdge(u, v) != want {
+				t.Errorf("TransitiveClosure has edge %d -> %d: %v, expected %v", u, v, !want, want)
+			}
+			if idx.Reachable(u, v) != (want || u == v) {
+				t.Errorf("Reachable(%d, %d) = %v, expected %v", u, v, !(want || u == v), want || u == v)
+			}
+		}
+	}
+	if _, err := NewReachabilityIndex(g, 0, 25); err == nil {
+		t.Error("NewReachabilityIndex accepted 0 labelings")
+	}
+}
+
+func TestReachabilityIndexMatchesTransitiveClosure(t *testing.T) {
+	r := rand.New(rand.NewSource(25))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(100)
+		ss, ds, _ := randomEdges(r, n, r.Intn(2*n))
+		var g Graph
+		var err error
+		if i%4 == 0 {
+			g, err = New(ss, ds)
+		} else {
+			g, err = NewDiGraph(ss, ds)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		nv := g.NumVertices()
+		reach := make([][]bool, nv)
+		for u := range reach {
+			_, vertLevel := BFS(g, uint32(u))
+			reach[u] = make([]bool, nv)
+			for v, level := range vertLevel {
+				reach[u][v] = level != unvisited
+			}
+		}
+		closure, err := TransitiveClosure(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for u := u0; u < nv; u++ {
+			for v := u0; v < nv; v++ {
+				// u has a path of one or more edges to v if one of its out neighbors reaches v.
+				want := false
+				for _, w := range g.OutNeighbors(u) {
+					want = want || reach[w][v]
+				}
+				if got := closure.HasEdge(u, v); got != want {
+					t.Fatalf("%v: TransitiveClosure has edge %d -> %d: %v, expected %v", g, u, v, got, want)
+				}
+			}
+		}
+		for _, labelings := range []int{1, 5} {
+			idx, err := NewReachabilityIndex(g, labelings, int64(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for u := u0; u < nv; u++ {
+				for v := u0; v < nv; v++ {
+					if got := idx.Reachable(u, v); got != reach[u][v] || got != (u == v || closure.HasEdge(u, v)) {
+						t.Fatalf("%v: Reachable(%d, %d) with %d labelings = %v, expected %v", g, u, v, labelings, got, reach[u][v])
+					}
+				}
+			}
+		}
+	}
+}
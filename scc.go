@@ -0,0 +1,293 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// StronglyConnectedComponents computes the strongly connected components of g with an iterative
+// version of Tarjan's algorithm. It returns a vector of component labels indexed by vertex,
+// numbered from 0 in order of the lowest vertex in each component, and a vector of the number of
+// vertices in each component indexed by label. The components of an undirected graph are its
+// connected components.
+func StronglyConnectedComponents(g Graph) (labels, sizes []uint32) {
+	nv := g.NumVertices()
+	index := make([]uint32, nv)
+	low := make([]uint32, nv)
+	comp := make([]uint32, nv)
+	for i := u0; i < nv; i++ {
+		index[i] = unvisited
+		comp[i] = NoParent
+	}
+	stack := []uint32{}    // vertices visited but not yet assigned to a component
+	frames := []dfsFrame{} // the DFS path
+	counter := u0
+
+	for root := u0; root < nv; root++ {
+		if index[root] != unvisited {
+			continue
+		}
+		frames = append(frames, dfsFrame{u: root})
+		index[root], low[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			u := top.u
+			neighbors := g.OutNeighbors(u)
+			if top.next < len(neighbors) {
+				v := neighbors[top.next]
+				top.next++
+				switch {
+				case index[v] == unvisited:
+					index[v], low[v] = counter, counter
+					counter++
+					stack = append(stack, v)
+					frames = append(frames, dfsFrame{u: v})
+				case comp[v] == NoParent && index[v] < low[u]: // v is on the stack
+					low[u] = index[v]
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+			if low[u] == index[u] {
+				for {
+					v := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					comp[v] = u
+					if v == u {
+						break
+					}
+				}
+			}
+			if len(frames) > 0 {
+				if parent := frames[len(frames)-1].u; low[u] < low[parent] {
+					low[parent] = low[u]
+				}
+			}
+		}
+	}
+	return relabel(comp)
+}
+
+// collectBlocks calls fn on blocks of [0, n) as blockIter does and returns the concatenation of
+// the vertices appended by fn to its argument.
+func collectBlocks(n int, procs int, fn func(low, high int, out []uint32) []uint32) []uint32 {
+	var mu sync.Mutex
+	all := []uint32{}
+	blockIter(n, procs, func(low, high int) {
+		out := fn(low, high, nil)
+		mu.Lock()
+		all = append(all, out...)
+		mu.Unlock()
+	})
+	return all
+}
+
+// trimSCCs assigns each vertex with no in edges or no out edges from other unassigned vertices
+// to its own component in comp, repeatedly, using `procs` goroutines. Unassigned vertices have
+// a component of NoParent.
+func trimSCCs(g Graph, comp []uint32, procs int) {
+	nv := len(comp)
+	inDeg := make([]uint32, nv)
+	outDeg := make([]uint32, nv)
+	count := func(v uint32, neighbors []uint32) uint32 {
+		n := u0
+		for _, u := range neighbors {
+			if u != v && comp[u] == NoParent {
+				n++
+			}
+		}
+		return n
+	}
+	frontier := collectBlocks(nv, procs, func(low, high int, out []uint32) []uint32 {
+		for v := uint32(low); v < uint32(high); v++ {
+			if comp[v] != NoParent {
+				continue
+			}
+			inDeg[v], outDeg[v] = count(v, g.InNeighbors(v)), count(v, g.OutNeighbors(v))
+			if inDeg[v] == 0 || outDeg[v] == 0 {
+				out = append(out, v)
+			}
+		}
+		return out
+	})
+	for _, v := range frontier {
+		comp[v] = v
+	}
+
+	// removing v lowers the counts of its neighbors, which are trimmed when a count reaches 0.
+	for len(frontier) > 0 {
+		current := frontier
+		frontier = collectBlocks(len(current), procs, func(low, high int, out []uint32) []uint32 {
+			for _, v := range current[low:high] {
+				for _, w := range g.OutNeighbors(v) {
+					if w != v && atomic.AddUint32(&inDeg[w], ^u0) == 0 && atomic.CompareAndSwapUint32(&comp[w], NoParent, w) {
+						out = append(out, w)
+					}
+				}
+				for _, w := range g.InNeighbors(v) {
+					if w != v && atomic.AddUint32(&outDeg[w], ^u0) == 0 && atomic.CompareAndSwapUint32(&comp[w], NoParent, w) {
+						out = append(out, w)
+					}
+				}
+			}
+			return out
+		})
+	}
+}
+
+// forwardBackwardSCC assigns the component of the unassigned vertex with the most in and out
+// edges, which is likely to be in the largest component, as the intersection of the vertices it
+// reaches and the vertices that reach it, found with ParallelBFS over the forward and backward
+// matrices.
+func forwardBackwardSCC(g Graph, comp []uint32, procs int) {
+	var mu sync.Mutex
+	pivot, best := NoParent, uint64(0)
+	blockIter(len(comp), procs, func(low, high int) {
+		p, b := NoParent, uint64(0)
+		for v := uint32(low); v < uint32(high); v++ {
+			if d := uint64(g.InDegree(v)) * uint64(g.OutDegree(v)); comp[v] == NoParent && (p == NoParent || d > b) {
+				p, b = v, d
+			}
+		}
+		mu.Lock()
+		if p != NoParent && (pivot == NoParent || b > best || (b == best && p < pivot)) {
+			pivot, best = p, b
+		}
+		mu.Unlock()
+	})
+	if pivot == NoParent {
+		return
+	}
+
+	_, fw := ParallelBFS(g, pivot, procs)
+	_, bw := ParallelBFS(reverse(g), pivot, procs)
+	blockIter(len(comp), procs, func(low, high int) {
+		for v := low; v < high; v++ {
+			if comp[v] == NoParent && fw[v] != unvisited && bw[v] != unvisited {
+				comp[v] = pivot
+			}
+		}
+	})
+}
+
+// atomicMax raises *addr to x and returns true if x is greater than *addr.
+func atomicMax(addr *uint32, x uint32) bool {
+	for {
+		old := atomic.LoadUint32(addr)
+		if x <= old {
+			return false
+		}
+		if atomic.CompareAndSwapUint32(addr, old, x) {
+			return true
+		}
+	}
+}
+
+// colorSCCs assigns the remaining unassigned vertices in comp to components, using `procs`
+// goroutines. In each round, the highest vertex number that reaches each unassigned vertex
+// through unassigned vertices is propagated as its color; each vertex that keeps its own color
+// is the root of a component made of the vertices of its color that reach it.
+func colorSCCs(g Graph, comp []uint32, procs int) {
+	colors := make([]uint32, len(comp))
+	remaining := collectBlocks(len(comp), procs, func(low, high int, out []uint32) []uint32 {
+		for v := uint32(low); v < uint32(high); v++ {
+			if comp[v] == NoParent {
+				out = append(out, v)
+			}
+		}
+		return out
+	})
+
+	for len(remaining) > 0 {
+		for _, v := range remaining {
+			colors[v] = v
+		}
+		for changed := uint32(1); changed != 0; {
+			changed = 0
+			blockIter(len(remaining), procs, func(low, high int) {
+				for _, v := range remaining[low:high] {
+					c := atomic.LoadUint32(&colors[v])
+					for _, w := range g.OutNeighbors(v) {
+						if comp[w] == NoParent && atomicMax(&colors[w], c) {
+							atomic.StoreUint32(&changed, 1)
+						}
+					}
+				}
+			})
+		}
+
+		roots := []uint32{}
+		for _, v := range remaining {
+			if colors[v] == v {
+				roots = append(roots, v)
+			}
+		}
+		// colors are disjoint, so each root's search touches different vertices.
+		parallelFor(uint32(len(roots)), procs, func(_ int, i uint32) {
+			root := roots[i]
+			comp[root] = root
+			queue := []uint32{root}
+			for len(queue) > 0 {
+				u := queue[len(queue)-1]
+				queue = queue[:len(queue)-1]
+				for _, v := range g.InNeighbors(u) {
+					if colors[v] == root && comp[v] == NoParent {
+						comp[v] = root
+						queue = append(queue, v)
+					}
+				}
+			}
+		})
+
+		n := 0
+		for _, v := range remaining {
+			if comp[v] == NoParent {
+				remaining[n] = v
+				n++
+			}
+		}
+		remaining = remaining[:n]
+	}
+}
+
+// ParallelStronglyConnectedComponents computes the strongly connected components of g using
+// `procs` goroutines, and returns the same vectors as StronglyConnectedComponents. It first
+// trims vertices that cannot be in a component with other vertices, then finds the component of
+// a well-connected pivot with forward and backward searches, trims again and assigns the
+// remaining vertices by coloring.
+func ParallelStronglyConnectedComponents(g Graph, procs int) (labels, sizes []uint32) {
+	comp := make([]uint32, g.NumVertices())
+	for i := range comp {
+		comp[i] = NoParent
+	}
+	trimSCCs(g, comp, procs)
+	forwardBackwardSCC(g, comp, procs)
+	trimSCCs(g, comp, procs)
+	colorSCCs(g, comp, procs)
+	return relabel(comp)
+}
+
+// Condensation returns the condensation of g, a directed acyclic graph with a vertex for each
+// strongly connected component of g and an edge between two components if g has an edge between
+// their vertices, and the vector of component labels indexed by vertex of g, as returned by
+// StronglyConnectedComponents.
+func Condensation(g Graph) (SimpleDiGraph, []uint32, error) {
+	labels, sizes := StronglyConnectedComponents(g)
+	ss, ds := []uint32{}, []uint32{}
+	for u := u0; u < g.NumVertices(); u++ {
+		for _, v := range g.OutNeighbors(u) {
+			if labels[u] != labels[v] {
+				ss = append(ss, labels[u])
+				ds = append(ds, labels[v])
+			}
+		}
+	}
+	dag, err := newDiGraph(uint32(len(sizes)), ss, ds)
+	if err != nil {
+		return SimpleDiGraph{}, nil, err
+	}
+	return dag, labels, nil
+}
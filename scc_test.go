@@ -0,0 +1,31 @@
+package graph
+
+import "testing"
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	// the cycle 0 -> 1 -> 2 -> 0 leads to the cycle 3 <-> 4; 5 has only a self loop.
+	g, err := NewDiGraph([]uint32{0, 1, 2, 2, 3, 4, 5}, []uint32{1, 2, 0, 3, 4, 3, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLabels, wantSizes := []uint32{0, 0, 0, 1, 1, 2}, []uint32{3, 2, 1}
+	labels, sizes := StronglyConnectedComponents(g)
+	checkSame(t, g, "StronglyConnectedComponents labels", 1, labels, wantLabels)
+	checkSame(t, g, "StronglyConnectedComponents sizes", 1, sizes, wantSizes)
+	forProcs(func(procs int) {
+		labels, sizes := ParallelStronglyConnectedComponents(g, procs)
+		checkSame(t, g, "ParallelStronglyConnectedComponents labels", procs, labels, wantLabels)
+		checkSame(t, g, "ParallelStronglyConnectedComponents sizes", procs, sizes, wantSizes)
+	})
+}
+
+func TestParallelStronglyConnectedComponentsMatchesTarjan(t *testing.T) {
+	for _, g := range parallelTestGraphs(t, 22) {
+		wantLabels, wantSizes := StronglyConnectedComponents(g)
+		forProcs(func(procs int) {
+			labels, sizes := ParallelStronglyConnectedComponents(g, procs)
+			checkSame(t, g, "ParallelStronglyConnectedComponents labels", procs, labels, wantLabels)
+			checkSame(t, g, "ParallelStronglyConnectedComponents sizes", procs, sizes, wantSizes)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sbromberger/graphmatrix"
+)
+
+// SimpleDiGraph is a graph structure representing a directed graph.
+type SimpleDiGraph struct {
+	fmx, bmx graphmatrix.GraphMatrix
+}
+
+var _ Graph = SimpleDiGraph{}
+
+func (g SimpleDiGraph) String() string {
+	return fmt.Sprintf("(%d, %d) directed graph", g.NumVertices(), g.NumEdges())
+}
+
+// NewDiGraph creates a directed graph from vectors of source and dest vertices.
+// Duplicate edges are removed.
+func NewDiGraph(ss, ds []uint32) (SimpleDiGraph, error) {
+	if len(ss) != len(ds) {
+		return SimpleDiGraph{}, errors.New("source and destination vectors must be the same length")
+	}
+	return newDiGraph(numVertices(ss, ds), ss, ds)
+}
+
+// newDiGraph creates a directed graph with nv vertices from vectors of source and dest vertices.
+func newDiGraph(nv uint32, ss, ds []uint32) (SimpleDiGraph, error) {
+	fSs := make([]uint32, len(ss))
+	fDs := make([]uint32, len(ds))
+	copy(fSs, ss)
+	copy(fDs, ds)
+	rSs := make([]uint32, len(ss))
+	rDs := make([]uint32, len(ds))
+	copy(rSs, ss)
+	copy(rDs, ds)
+
+	err := graphmatrix.SortIJ(&fSs, &fDs)
+	if err != nil {
+		return SimpleDiGraph{}, err
+	}
+	err = graphmatrix.SortIJ(&rDs, &rSs)
+	if err != nil {
+		return SimpleDiGraph{}, err
+	}
+	fSs, fDs = dedupSortedIJ(fSs, fDs)
+	rDs, rSs = dedupSortedIJ(rDs, rSs)
+	fmx := matrixFromSortedIJ(fSs, fDs, nv)
+	bmx := matrixFromSortedIJ(rDs, rSs, nv)
+	return SimpleDiGraph{fmx: fmx, bmx: bmx}, nil
+}
+
+// NewDiGraphFromEdgeList creates a directed graph from an EdgeList.
+func NewDiGraphFromEdgeList(l EdgeList) (SimpleDiGraph, error) {
+	ss, ds := splitEdgeList(l)
+	return NewDiGraph(ss, ds)
+}
+
+// OutDegree returns the out degree of vertex u.
+func (g SimpleDiGraph) OutDegree(u uint32) uint32 { return uint32(len(g.OutNeighbors(u))) }
+
+// InDegree returns the indegree of vertex u.
+func (g SimpleDiGraph) InDegree(u uint32) uint32 { return uint32(len(g.InNeighbors(u))) }
+
+// OutNeighbors returns the out neighbors of vertex u.
+func (g SimpleDiGraph) OutNeighbors(u uint32) []uint32 {
+	r, _ := g.fmx.GetRow(u)
+	return r
+}
+
+// InNeighbors returns the in neighbors of vertex u.
+func (g SimpleDiGraph) InNeighbors(u uint32) []uint32 {
+	r, _ := g.bmx.GetRow(u)
+	return r
+}
+
+// HasEdge returns true if an edge exists from u to v.
+func (g SimpleDiGraph) HasEdge(u, v uint32) bool {
+	return hasEdge(g.OutNeighbors(u), g.InNeighbors(v), u, v)
+}
+
+// AddEdge adds an edge from u to v to graph g.
+func (g *SimpleDiGraph) AddEdge(u, v uint32) error {
+	if err := g.fmx.SetIndex(u, v); err != nil {
+		return err
+	}
+	if err := g.bmx.SetIndex(v, u); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NumEdges returns the number of edges
+func (g SimpleDiGraph) NumEdges() uint64 {
+	return g.fmx.N()
+}
+
+// NumVertices returns the number of vertices
+func (g SimpleDiGraph) NumVertices() uint32 {
+	return g.fmx.Dim()
+}
+
+// Edges returns an iterator of edges
+func (g SimpleDiGraph) Edges() EdgeIter {
+	return newSimpleEdgeIter(g.fmx, false)
+}
+
+// Vertices returns an iterator of vertices.
+func (g SimpleDiGraph) Vertices() VertexIter {
+	return &SimpleVertexIter{max: g.NumVertices()}
+}
+
+// FMat returns the forward matrix of the graph.
+func (g SimpleDiGraph) FMat() graphmatrix.GraphMatrix {
+	return g.fmx
+}
+
+// BMat returns the backward matrix of the graph.
+func (g SimpleDiGraph) BMat() graphmatrix.GraphMatrix {
+	return g.bmx
+}
+
+func (g SimpleDiGraph) IsDirected() bool { return true }
+
+// DiGraphFromRaw creates a directed graph from the raw vectors of its forward and backward matrices.
+func DiGraphFromRaw(findptr []uint64, find []uint32, bindptr []uint64, bind []uint32) (SimpleDiGraph, error) {
+	fmx := graphmatrix.GraphMatrix{IndPtr: findptr, Indices: find}
+	bmx := graphmatrix.GraphMatrix{IndPtr: bindptr, Indices: bind}
+	return SimpleDiGraph{fmx: fmx, bmx: bmx}, nil
+}
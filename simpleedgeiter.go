@@ -4,13 +4,36 @@ import "github.com/sbromberger/graphmatrix"
 
 type SimpleEdgeIter struct {
 	mxiter graphmatrix.NZIter
+	upper  bool // if true, only return edges with src <= dst
+	next   SimpleEdge
+	done   bool
+}
+
+func newSimpleEdgeIter(mx graphmatrix.GraphMatrix, upper bool) *SimpleEdgeIter {
+	it := &SimpleEdgeIter{mxiter: mx.NewNZIter(), upper: upper}
+	it.advance()
+	return it
+}
+
+// advance moves the iterator to the next edge to be returned.
+func (it *SimpleEdgeIter) advance() {
+	for !it.mxiter.Done() {
+		r, c, _ := it.mxiter.Next()
+		if it.upper && r > c {
+			continue
+		}
+		it.next = SimpleEdge{src: r, dst: c}
+		return
+	}
+	it.done = true
 }
 
 func (it *SimpleEdgeIter) Next() Edge {
-	r, c, _ := it.mxiter.Next()
-	return SimpleEdge{src: r, dst: c}
+	e := it.next
+	it.advance()
+	return e
 }
 
 func (it *SimpleEdgeIter) Done() bool {
-	return it.mxiter.Done()
+	return it.done
 }
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/sbromberger/graphmatrix"
@@ -8,46 +9,48 @@ import (
 
 // SimpleGraph is a graph structure representing an undirected graph.
 type SimpleGraph struct {
-	fmx, bmx graphmatrix.GraphMatrix
+	fmx, bmx  graphmatrix.GraphMatrix
+	selfLoops uint64
 }
 
+var _ Graph = SimpleGraph{}
+
 func (g SimpleGraph) String() string {
 	return fmt.Sprintf("(%d, %d) graph", g.NumVertices(), g.NumEdges())
 }
 
-// MakeSimpleGraph creates an undirected graph from vectors of source and dest vertices.
+// New creates an undirected graph from vectors of source and dest vertices.
+// Each edge is stored in both directions; duplicate edges are removed.
 func New(ss, ds []uint32) (SimpleGraph, error) {
-	rSs := make([]uint32, len(ss))
-	rDs := make([]uint32, len(ds))
+	if len(ss) != len(ds) {
+		return SimpleGraph{}, errors.New("source and destination vectors must be the same length")
+	}
+	return newGraph(numVertices(ss, ds), ss, ds)
+}
+
+// newGraph creates an undirected graph with nv vertices from vectors of source and dest vertices.
+func newGraph(nv uint32, ss, ds []uint32) (SimpleGraph, error) {
+	n := len(ss)
+	rSs := make([]uint32, 2*n)
+	rDs := make([]uint32, 2*n)
 	copy(rSs, ss)
+	copy(rSs[n:], ds)
 	copy(rDs, ds)
+	copy(rDs[n:], ss)
 
-	err := graphmatrix.SortIJ(&ss, &ds)
+	err := graphmatrix.SortIJ(&rSs, &rDs)
 	if err != nil {
 		return SimpleGraph{}, err
 	}
-	err = graphmatrix.SortIJ(&rDs, &rSs)
-	if err != nil {
-		return SimpleGraph{}, err
-	}
-	fmx, err := graphmatrix.NewFromSortedIJ(ss, ds)
-	if err != nil {
-		return SimpleGraph{}, err
-	}
-	bmx, err := graphmatrix.NewFromSortedIJ(rDs, rSs)
-	if err != nil {
-		return SimpleGraph{}, err
-	}
-	return SimpleGraph{fmx: fmx, bmx: bmx}, nil
+	rSs, rDs = dedupSortedIJ(rSs, rDs)
+	fmx := matrixFromSortedIJ(rSs, rDs, nv)
+	bmx := matrixFromSortedIJ(rSs, rDs, nv)
+	return SimpleGraph{fmx: fmx, bmx: bmx, selfLoops: countSelfLoops(fmx)}, nil
 }
 
+// NewFromEdgeList creates an undirected graph from an EdgeList.
 func NewFromEdgeList(l EdgeList) (SimpleGraph, error) {
-	ss := make([]uint32, len(l))
-	ds := make([]uint32, len(l))
-	for i, e := range l {
-		ss[i] = e.Src()
-		ds[i] = e.Dst()
-	}
+	ss, ds := splitEdgeList(l)
 	return New(ss, ds)
 }
 
@@ -71,20 +74,10 @@ func (g SimpleGraph) InNeighbors(u uint32) []uint32 {
 
 // HasEdge returns true if an edge exists between u and v.
 func (g SimpleGraph) HasEdge(u, v uint32) bool {
-	un := g.OutNeighbors(u)
-	vn := g.InNeighbors(v)
-	lenun := uint64(len(un))
-	lenvn := uint64(len(vn))
-	var found bool
-	if lenvn > lenun {
-		_, found = graphmatrix.SearchSorted32(un, v, 0, lenun)
-	} else {
-		_, found = graphmatrix.SearchSorted32(vn, u, 0, lenvn)
-	}
-	return found
+	return hasEdge(g.OutNeighbors(u), g.InNeighbors(v), u, v)
 }
 
-// AddEdge adds an edge to graph g
+// AddEdge adds an undirected edge between u and v to graph g.
 func (g *SimpleGraph) AddEdge(u, v uint32) error {
 	if err := g.fmx.SetIndex(u, v); err != nil {
 		return err
@@ -92,12 +85,22 @@ func (g *SimpleGraph) AddEdge(u, v uint32) error {
 	if err := g.bmx.SetIndex(v, u); err != nil {
 		return err
 	}
+	if u == v {
+		g.selfLoops++
+		return nil
+	}
+	if err := g.fmx.SetIndex(v, u); err != nil {
+		return err
+	}
+	if err := g.bmx.SetIndex(u, v); err != nil {
+		return err
+	}
 	return nil
 }
 
-// NumEdges returns the number of edges
+// NumEdges returns the number of edges. Each undirected edge is counted once.
 func (g SimpleGraph) NumEdges() uint64 {
-	return g.fmx.N()
+	return (g.fmx.N() + g.selfLoops) / 2
 }
 
 // NumVertices returns the number of vertices
@@ -105,9 +108,14 @@ func (g SimpleGraph) NumVertices() uint32 {
 	return g.fmx.Dim()
 }
 
-// Edges returns an iterator of edges
+// Edges returns an iterator of edges. Each undirected edge is returned once, with Src() <= Dst().
 func (g SimpleGraph) Edges() EdgeIter {
-	return &SimpleEdgeIter{mxiter: g.fmx.NewNZIter()}
+	return newSimpleEdgeIter(g.fmx, true)
+}
+
+// Vertices returns an iterator of vertices.
+func (g SimpleGraph) Vertices() VertexIter {
+	return &SimpleVertexIter{max: g.NumVertices()}
 }
 
 // FMat returns the forward matrix of the graph.
@@ -122,8 +130,93 @@ func (g SimpleGraph) BMat() graphmatrix.GraphMatrix {
 
 func (g SimpleGraph) IsDirected() bool { return false }
 
+// FromRaw creates an undirected graph from the raw vectors of its forward and backward matrices.
 func FromRaw(findptr []uint64, find []uint32, bindptr []uint64, bind []uint32) (SimpleGraph, error) {
 	fmx := graphmatrix.GraphMatrix{IndPtr: findptr, Indices: find}
 	bmx := graphmatrix.GraphMatrix{IndPtr: bindptr, Indices: bind}
-	return SimpleGraph{fmx, bmx}, nil
+	return SimpleGraph{fmx: fmx, bmx: bmx, selfLoops: countSelfLoops(fmx)}, nil
+}
+
+// hasEdge returns true if an edge exists between u and v, searching the shorter
+// of u's out neighbors un and v's in neighbors vn.
+func hasEdge(un, vn []uint32, u, v uint32) bool {
+	lenun := uint64(len(un))
+	lenvn := uint64(len(vn))
+	var found bool
+	if lenvn > lenun {
+		_, found = graphmatrix.SearchSorted32(un, v, 0, lenun)
+	} else {
+		_, found = graphmatrix.SearchSorted32(vn, u, 0, lenvn)
+	}
+	return found
+}
+
+// splitEdgeList returns vectors of source and dest vertices for an EdgeList.
+func splitEdgeList(l EdgeList) (ss, ds []uint32) {
+	ss = make([]uint32, len(l))
+	ds = make([]uint32, len(l))
+	for i, e := range l {
+		ss[i] = e.Src()
+		ds[i] = e.Dst()
+	}
+	return ss, ds
+}
+
+// numVertices returns one more than the largest vertex in ss and ds.
+func numVertices(ss, ds []uint32) uint32 {
+	if len(ss) == 0 {
+		return 0
+	}
+	m := u0
+	for i := range ss {
+		if ss[i] > m {
+			m = ss[i]
+		}
+		if ds[i] > m {
+			m = ds[i]
+		}
+	}
+	return m + 1
+}
+
+// dedupSortedIJ removes repeated (i, j) pairs from sorted vectors in place.
+func dedupSortedIJ(ss, ds []uint32) ([]uint32, []uint32) {
+	if len(ss) == 0 {
+		return ss, ds
+	}
+	k := 1
+	for i := 1; i < len(ss); i++ {
+		if ss[i] != ss[k-1] || ds[i] != ds[k-1] {
+			ss[k], ds[k] = ss[i], ds[i]
+			k++
+		}
+	}
+	return ss[:k], ds[:k]
+}
+
+// matrixFromSortedIJ builds an nv x nv GraphMatrix from sorted, deduplicated vectors of
+// row and column indices.
+func matrixFromSortedIJ(ss, ds []uint32, nv uint32) graphmatrix.GraphMatrix {
+	indptr := make([]uint64, uint64(nv)+1)
+	for _, s := range ss {
+		indptr[s+1]++
+	}
+	for i := 1; i < len(indptr); i++ {
+		indptr[i] += indptr[i-1]
+	}
+	indices := make([]uint32, len(ds))
+	copy(indices, ds)
+	return graphmatrix.GraphMatrix{IndPtr: indptr, Indices: indices}
+}
+
+// countSelfLoops returns the number of diagonal entries in mx.
+func countSelfLoops(mx graphmatrix.GraphMatrix) uint64 {
+	n := uint64(0)
+	for u := u0; u < mx.Dim(); u++ {
+		r, _ := mx.GetRow(u)
+		if _, found := graphmatrix.SearchSorted32(r, u, 0, uint64(len(r))); found {
+			n++
+		}
+	}
+	return n
 }
@@ -1,18 +1,19 @@
 package graph
 
+// SimpleVertexIter iterates over the vertices 0 through max-1.
 type SimpleVertexIter struct {
 	curr uint32
 	max  uint32
 }
 
+// Next returns the next vertex.
 func (it *SimpleVertexIter) Next() uint32 {
+	v := it.curr
 	it.curr++
-	if it.curr > it.max {
-		it.curr = it.max
-	}
-	return it.curr
+	return v
 }
 
+// Done returns true if all vertices have been returned.
 func (it *SimpleVertexIter) Done() bool {
-	return it.curr > it.max
+	return it.curr >= it.max
 }
@@ -0,0 +1,87 @@
+package graph
+
+import "fmt"
+
+// simpleDiGraph lets SimpleWeightedDiGraph embed a SimpleDiGraph without exporting it, so that
+// edges cannot be added to it without weights.
+type simpleDiGraph = SimpleDiGraph
+
+// SimpleWeightedDiGraph is a graph structure representing a directed graph with a weight on each edge.
+type SimpleWeightedDiGraph struct {
+	simpleDiGraph
+	fws, bws []float32 // edge weights, parallel to the indices of fmx and bmx
+}
+
+var _ WeightedGraph = SimpleWeightedDiGraph{}
+
+func (g SimpleWeightedDiGraph) String() string {
+	return fmt.Sprintf("(%d, %d) weighted directed graph", g.NumVertices(), g.NumEdges())
+}
+
+// NewWeightedDiGraph creates a directed weighted graph from vectors of source vertices, dest vertices
+// and edge weights. If an edge is repeated, the smallest weight is kept.
+func NewWeightedDiGraph(ss, ds []uint32, ws []float32) (SimpleWeightedDiGraph, error) {
+	if err := checkWeighted(ss, ds, ws); err != nil {
+		return SimpleWeightedDiGraph{}, err
+	}
+	nv := numVertices(ss, ds)
+	fmx, fws, err := sortedWeightedMatrix(ss, ds, ws, nv)
+	if err != nil {
+		return SimpleWeightedDiGraph{}, err
+	}
+	bmx, bws, err := sortedWeightedMatrix(ds, ss, ws, nv)
+	if err != nil {
+		return SimpleWeightedDiGraph{}, err
+	}
+	return SimpleWeightedDiGraph{
+		simpleDiGraph: SimpleDiGraph{fmx: fmx, bmx: bmx},
+		fws:           fws,
+		bws:           bws,
+	}, nil
+}
+
+// OutEdgesWeighted returns the out neighbors of vertex u and the weights of the corresponding edges.
+func (g SimpleWeightedDiGraph) OutEdgesWeighted(u uint32) ([]uint32, []float32) {
+	return g.OutNeighbors(u), rowWeights(g.fmx, g.fws, u)
+}
+
+// InEdgesWeighted returns the in neighbors of vertex u and the weights of the corresponding edges.
+func (g SimpleWeightedDiGraph) InEdgesWeighted(u uint32) ([]uint32, []float32) {
+	return g.InNeighbors(u), rowWeights(g.bmx, g.bws, u)
+}
+
+// Weight returns the weight of the edge from u to v, and whether the edge exists.
+func (g SimpleWeightedDiGraph) Weight(u, v uint32) (float32, bool) {
+	return weightOf(g.fmx, g.fws, u, v)
+}
+
+// AddEdge adds an edge from u to v with weight w to graph g.
+func (g *SimpleWeightedDiGraph) AddEdge(u, v uint32, w float32) error {
+	if err := setWeighted(&g.fmx, &g.fws, u, v, w); err != nil {
+		return err
+	}
+	if err := setWeighted(&g.bmx, &g.bws, v, u, w); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FWeights returns the edge weights of the graph, parallel to the indices of the forward matrix.
+func (g SimpleWeightedDiGraph) FWeights() []float32 {
+	return g.fws
+}
+
+// BWeights returns the edge weights of the graph, parallel to the indices of the backward matrix.
+func (g SimpleWeightedDiGraph) BWeights() []float32 {
+	return g.bws
+}
+
+// WeightedDiGraphFromRaw creates a directed weighted graph from the raw vectors of its forward and
+// backward matrices and their edge weights.
+func WeightedDiGraphFromRaw(findptr []uint64, find []uint32, fws []float32, bindptr []uint64, bind []uint32, bws []float32) (SimpleWeightedDiGraph, error) {
+	g, err := DiGraphFromRaw(findptr, find, bindptr, bind)
+	if err != nil {
+		return SimpleWeightedDiGraph{}, err
+	}
+	return SimpleWeightedDiGraph{simpleDiGraph: g, fws: fws, bws: bws}, nil
+}
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/sbromberger/graphmatrix"
+)
+
+// simpleGraph lets SimpleWeightedGraph embed a SimpleGraph without exporting it, so that edges
+// cannot be added to it without weights.
+type simpleGraph = SimpleGraph
+
+// SimpleWeightedGraph is a graph structure representing an undirected graph with a weight on each edge.
+type SimpleWeightedGraph struct {
+	simpleGraph
+	fws, bws []float32 // edge weights, parallel to the indices of fmx and bmx
+}
+
+var _ WeightedGraph = SimpleWeightedGraph{}
+
+func (g SimpleWeightedGraph) String() string {
+	return fmt.Sprintf("(%d, %d) weighted graph", g.NumVertices(), g.NumEdges())
+}
+
+// NewWeighted creates an undirected weighted graph from vectors of source vertices, dest vertices
+// and edge weights. Each edge is stored in both directions; if an edge is repeated, the smallest
+// weight is kept.
+func NewWeighted(ss, ds []uint32, ws []float32) (SimpleWeightedGraph, error) {
+	if err := checkWeighted(ss, ds, ws); err != nil {
+		return SimpleWeightedGraph{}, err
+	}
+	n := len(ss)
+	rSs := make([]uint32, 2*n)
+	rDs := make([]uint32, 2*n)
+	rWs := make([]float32, 2*n)
+	copy(rSs, ss)
+	copy(rSs[n:], ds)
+	copy(rDs, ds)
+	copy(rDs[n:], ss)
+	copy(rWs, ws)
+	copy(rWs[n:], ws)
+
+	nv := numVertices(ss, ds)
+	fmx, fws, err := sortedWeightedMatrix(rSs, rDs, rWs, nv)
+	if err != nil {
+		return SimpleWeightedGraph{}, err
+	}
+	bmx := graphmatrix.GraphMatrix{IndPtr: make([]uint64, len(fmx.IndPtr)), Indices: make([]uint32, len(fmx.Indices))}
+	copy(bmx.IndPtr, fmx.IndPtr)
+	copy(bmx.Indices, fmx.Indices)
+	bws := make([]float32, len(fws))
+	copy(bws, fws)
+	return SimpleWeightedGraph{
+		simpleGraph: SimpleGraph{fmx: fmx, bmx: bmx, selfLoops: countSelfLoops(fmx)},
+		fws:         fws,
+		bws:         bws,
+	}, nil
+}
+
+// OutEdgesWeighted returns the out neighbors of vertex u and the weights of the corresponding edges.
+func (g SimpleWeightedGraph) OutEdgesWeighted(u uint32) ([]uint32, []float32) {
+	return g.OutNeighbors(u), rowWeights(g.fmx, g.fws, u)
+}
+
+// InEdgesWeighted returns the in neighbors of vertex u and the weights of the corresponding edges.
+func (g SimpleWeightedGraph) InEdgesWeighted(u uint32) ([]uint32, []float32) {
+	return g.InNeighbors(u), rowWeights(g.bmx, g.bws, u)
+}
+
+// Weight returns the weight of the edge between u and v, and whether the edge exists.
+func (g SimpleWeightedGraph) Weight(u, v uint32) (float32, bool) {
+	return weightOf(g.fmx, g.fws, u, v)
+}
+
+// AddEdge adds an undirected edge between u and v with weight w to graph g.
+func (g *SimpleWeightedGraph) AddEdge(u, v uint32, w float32) error {
+	if err := setWeighted(&g.fmx, &g.fws, u, v, w); err != nil {
+		return err
+	}
+	if err := setWeighted(&g.bmx, &g.bws, v, u, w); err != nil {
+		return err
+	}
+	if u == v {
+		g.selfLoops++
+		return nil
+	}
+	if err := setWeighted(&g.fmx, &g.fws, v, u, w); err != nil {
+		return err
+	}
+	if err := setWeighted(&g.bmx, &g.bws, u, v, w); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FWeights returns the edge weights of the graph, parallel to the indices of the forward matrix.
+func (g SimpleWeightedGraph) FWeights() []float32 {
+	return g.fws
+}
+
+// BWeights returns the edge weights of the graph, parallel to the indices of the backward matrix.
+func (g SimpleWeightedGraph) BWeights() []float32 {
+	return g.bws
+}
+
+// WeightedFromRaw creates an undirected weighted graph from the raw vectors of its forward and
+// backward matrices and their edge weights.
+func WeightedFromRaw(findptr []uint64, find []uint32, fws []float32, bindptr []uint64, bind []uint32, bws []float32) (SimpleWeightedGraph, error) {
+	g, err := FromRaw(findptr, find, bindptr, bind)
+	if err != nil {
+		return SimpleWeightedGraph{}, err
+	}
+	return SimpleWeightedGraph{simpleGraph: g, fws: fws, bws: bws}, nil
+}
@@ -0,0 +1,203 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// DefaultTol is the default value of PowerIterOptions.Tol.
+	DefaultTol = 1e-6
+	// DefaultMaxIter is the default value of PowerIterOptions.MaxIter.
+	DefaultMaxIter = 100
+)
+
+// PowerIterOptions configures EigenvectorCentrality, KatzCentrality and HITS. Zero values are
+// replaced by the defaults.
+type PowerIterOptions struct {
+	// Tol: stop when the L1 norm of the change in scores in an iteration is less than Tol.
+	Tol float64
+	// MaxIter: return ErrNotConverged after MaxIter iterations.
+	MaxIter int
+	// Start is the starting vector, with a non-negative entry for each vertex and a positive
+	// sum. If it is nil, a uniform vector is used.
+	Start []float64
+	// Procs is the number of goroutines across which the vertices are partitioned.
+	Procs int
+}
+
+func (o PowerIterOptions) withDefaults() PowerIterOptions {
+	if o.Tol <= 0 {
+		o.Tol = DefaultTol
+	}
+	if o.MaxIter <= 0 {
+		o.MaxIter = DefaultMaxIter
+	}
+	return o
+}
+
+// pullSum sets next[v] to the sum of x over the in neighbors of each vertex v, or over the out
+// neighbors if out is true, using `procs` goroutines.
+func pullSum(g Graph, x, next []float64, out bool, procs int) {
+	blockIter(len(x), procs, func(low, high int) {
+		for v := uint32(low); v < uint32(high); v++ {
+			neighbors := g.InNeighbors(v)
+			if out {
+				neighbors = g.OutNeighbors(v)
+			}
+			sum := 0.0
+			for _, u := range neighbors {
+				sum += x[u]
+			}
+			next[v] = sum
+		}
+	})
+}
+
+// scaleVector divides x by its L2 norm if l2 is true, or by its sum otherwise, unless it is 0.
+func scaleVector(x []float64, l2 bool, procs int) {
+	norm := blockSum(len(x), procs, func(low, high int) float64 {
+		s := 0.0
+		for _, xv := range x[low:high] {
+			if l2 {
+				s += xv * xv
+			} else {
+				s += xv
+			}
+		}
+		return s
+	})
+	if l2 {
+		norm = math.Sqrt(norm)
+	}
+	if norm == 0 {
+		return
+	}
+	blockIter(len(x), procs, func(low, high int) {
+		for i := low; i < high; i++ {
+			x[i] /= norm
+		}
+	})
+}
+
+// l1Distance returns the L1 norm of x - y.
+func l1Distance(x, y []float64, procs int) float64 {
+	return blockSum(len(x), procs, func(low, high int) float64 {
+		d := 0.0
+		for i := low; i < high; i++ {
+			d += math.Abs(x[i] - y[i])
+		}
+		return d
+	})
+}
+
+// EigenvectorCentrality computes the eigenvector centrality of every vertex: the entries of
+// the principal eigenvector of the transpose of the adjacency matrix, so that the score of a
+// vertex is proportional to the sum of the scores of its in neighbors. It iterates with the
+// adjacency matrix plus the identity, which has the same principal eigenvector and converges
+// on bipartite graphs too. The scores have an L2 norm of 1. See PowerIterOptions for the
+// tolerance, iterations, starting vector and parallelism.
+func EigenvectorCentrality(g Graph, opts PowerIterOptions) (PowerIterResult, error) {
+	opts = opts.withDefaults()
+	nv := g.NumVertices()
+	x, err := probabilityVector(opts.Start, nv, "start")
+	if err != nil {
+		return PowerIterResult{}, err
+	}
+	scaleVector(x, true, opts.Procs)
+	next := make([]float64, nv)
+
+	res := PowerIterResult{Scores: x}
+	for res.Iterations < opts.MaxIter {
+		pullSum(g, x, next, false, opts.Procs)
+		for v := range next {
+			next[v] += x[v]
+		}
+		scaleVector(next, true, opts.Procs)
+		res.Residual = l1Distance(next, x, opts.Procs)
+		x, next = next, x
+		res.Scores = x
+		res.Iterations++
+		if res.Residual < opts.Tol {
+			res.Converged = true
+			return res, nil
+		}
+	}
+	return res, ErrNotConverged
+}
+
+// KatzCentrality computes the Katz centrality of every vertex, the solution of
+// x[v] = alpha * (sum of x over the in neighbors of v) + beta, which counts the walks ending at
+// each vertex, attenuated by alpha for each edge. It converges only if alpha is less than the
+// inverse of the largest eigenvalue of the adjacency matrix. The scores are scaled to an L2 norm
+// of 1. See PowerIterOptions for the tolerance, iterations, starting vector and parallelism.
+func KatzCentrality(g Graph, alpha, beta float64, opts PowerIterOptions) (PowerIterResult, error) {
+	if alpha <= 0 {
+		return PowerIterResult{}, errors.New("alpha must be positive")
+	}
+	opts = opts.withDefaults()
+	nv := g.NumVertices()
+	x, err := probabilityVector(opts.Start, nv, "start")
+	if err != nil {
+		return PowerIterResult{}, err
+	}
+	next := make([]float64, nv)
+
+	res := PowerIterResult{}
+	for res.Iterations < opts.MaxIter {
+		pullSum(g, x, next, false, opts.Procs)
+		for v := range next {
+			next[v] = alpha*next[v] + beta
+		}
+		res.Residual = l1Distance(next, x, opts.Procs)
+		x, next = next, x
+		res.Iterations++
+		if res.Residual < opts.Tol {
+			res.Converged = true
+			break
+		}
+	}
+	scaleVector(x, true, opts.Procs)
+	res.Scores = x
+	if !res.Converged {
+		return res, ErrNotConverged
+	}
+	return res, nil
+}
+
+// HITS computes the hub and authority scores of every vertex with Kleinberg's algorithm: the
+// authority score of a vertex is proportional to the sum of the hub scores of its in neighbors,
+// and its hub score to the sum of the authority scores of its out neighbors. Both sets of scores
+// sum to 1. Start gives the starting hub scores; convergence is measured on the hub scores, and
+// the Iterations, Residual and Converged fields of the two results are the same. See
+// PowerIterOptions for the tolerance, iterations, starting vector and parallelism.
+func HITS(g Graph, opts PowerIterOptions) (hubs, authorities PowerIterResult, err error) {
+	opts = opts.withDefaults()
+	nv := g.NumVertices()
+	h, err := probabilityVector(opts.Start, nv, "start")
+	if err != nil {
+		return PowerIterResult{}, PowerIterResult{}, err
+	}
+	a := make([]float64, nv)
+	next := make([]float64, nv)
+
+	for hubs.Iterations < opts.MaxIter {
+		pullSum(g, h, a, false, opts.Procs)
+		scaleVector(a, false, opts.Procs)
+		pullSum(g, a, next, true, opts.Procs)
+		scaleVector(next, false, opts.Procs)
+		hubs.Residual = l1Distance(next, h, opts.Procs)
+		h, next = next, h
+		hubs.Iterations++
+		if hubs.Residual < opts.Tol {
+			hubs.Converged = true
+			break
+		}
+	}
+	authorities = hubs
+	hubs.Scores, authorities.Scores = h, a
+	if !hubs.Converged {
+		return hubs, authorities, ErrNotConverged
+	}
+	return hubs, authorities, nil
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEigenvectorCentrality(t *testing.T) {
+	// the star with center 0 and 4 leaves has eigenvalue 2 with the center scoring twice each leaf.
+	star, err := New([]uint32{0, 0, 0, 0}, []uint32{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := EigenvectorCentrality(star, PowerIterOptions{Tol: 1e-12, MaxIter: 1000})
+	if err != nil || !res.Converged {
+		t.Fatalf("EigenvectorCentrality = %v, %v, expected convergence", res, err)
+	}
+	l := 1 / math.Sqrt(8)
+	assertScores(t, star, []float64{2 * l, l, l, l, l}, res.Scores)
+
+	res, err = EigenvectorCentrality(star, PowerIterOptions{MaxIter: 1})
+	if !errors.Is(err, ErrNotConverged) || res.Iterations != 1 {
+		t.Errorf("EigenvectorCentrality with 1 iteration = %v, %v, expected ErrNotConverged", res, err)
+	}
+	if _, err := EigenvectorCentrality(star, PowerIterOptions{Start: []float64{1}}); err == nil {
+		t.Error("EigenvectorCentrality accepted a start vector of the wrong length")
+	}
+}
+
+func TestKatzCentrality(t *testing.T) {
+	// on the path 0 -> 1 -> 2 with alpha 0.5 and beta 1, x = (1, 1.5, 1.75) before scaling.
+	g, err := NewDiGraph([]uint32{0, 1}, []uint32{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := KatzCentrality(g, 0.5, 1, PowerIterOptions{Tol: 1e-12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	norm := math.Sqrt(1 + 1.5*1.5 + 1.75*1.75)
+	assertScores(t, g, []float64{1 / norm, 1.5 / norm, 1.75 / norm}, res.Scores)
+	if _, err := KatzCentrality(g, 0, 1, PowerIterOptions{}); err == nil {
+		t.Error("KatzCentrality accepted an alpha of 0")
+	}
+}
+
+func TestHITS(t *testing.T) {
+	// hubs 0 and 1 point to authorities 2 and 3. A A^T restricted to the hubs is [[2 1] [1 1]],
+	// whose principal eigenvector is (phi, 1), and the authorities come out the same.
+	g, err := NewDiGraph([]uint32{0, 0, 1}, []uint32{2, 3, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hubs, authorities, err := HITS(g, PowerIterOptions{Tol: 1e-12, MaxIter: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	phi := (1 + math.Sqrt(5)) / 2
+	assertScores(t, g, []float64{1 / phi, 1 / (phi * phi), 0, 0}, hubs.Scores)
+	assertScores(t, g, []float64{0, 0, 1 / phi, 1 / (phi * phi)}, authorities.Scores)
+	if hubs.Iterations != authorities.Iterations || !authorities.Converged {
+		t.Errorf("HITS hubs %v and authorities %v differ", hubs, authorities)
+	}
+}
+
+func TestSpectralCentralityMatchesDefinition(t *testing.T) {
+	for _, g := range randomGraphs(t, 20, 20, 40) {
+		nv := g.NumVertices()
+		maxIn := 0
+		for v := u0; v < nv; v++ {
+			if n := len(g.InNeighbors(v)); n > maxIn {
+				maxIn = n
+			}
+		}
+		// the largest eigenvalue is at most the largest row sum, maxIn.
+		alpha := 0.5 / float64(maxIn+1)
+		a := make([][]float64, nv)
+		b := make([]float64, nv)
+		for v := range a {
+			a[v] = make([]float64, nv)
+			a[v][v] = 1
+			b[v] = 1
+			for _, u := range g.InNeighbors(uint32(v)) {
+				a[v][u] -= alpha
+			}
+		}
+		katz := solveLinear(a, b)
+		scaleVector(katz, true, 1)
+
+		forProcs(func(procs int) {
+			opts := PowerIterOptions{Tol: 1e-12, MaxIter: 1000, Procs: procs}
+			res, err := KatzCentrality(g, alpha, 1, opts)
+			if err != nil {
+				t.Fatalf("%v: KatzCentrality(procs=%d): %v", g, procs, err)
+			}
+			assertScores(t, g, katz, res.Scores)
+
+			if g.IsDirected() {
+				// the power iteration can converge very slowly on directed graphs.
+				return
+			}
+			// x is an eigenvector if A x = lambda x for lambda = x . A x.
+			res, err = EigenvectorCentrality(g, PowerIterOptions{Tol: 1e-13, MaxIter: 100000, Procs: procs})
+			if err != nil {
+				t.Fatalf("%v: EigenvectorCentrality(procs=%d): %v", g, procs, err)
+			}
+			x := res.Scores
+			y := make([]float64, nv)
+			pullSum(g, x, y, false, 1)
+			lambda := 0.0
+			for v := range x {
+				lambda += x[v] * y[v]
+			}
+			for v := range x {
+				if math.Abs(y[v]-lambda*x[v]) > 1e-5 {
+					t.Fatalf("%v: EigenvectorCentrality(procs=%d) = %v is not an eigenvector", g, procs, x)
+				}
+			}
+		})
+	}
+}
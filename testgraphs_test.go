@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// maxTestProcs is the largest number of goroutines with which parallel algorithms are tested.
+const maxTestProcs = 8
+
+// parallelTestGraphs returns pairs of random undirected and directed weighted graphs on which
+// parallel algorithms are compared with their serial counterparts: dense and sparse graphs of up
+// to 200 vertices, and a few of up to 5000 vertices, whose frontiers span several blocks and
+// which have more vertices than Afforest samples.
+func parallelTestGraphs(t *testing.T, seed int64) []WeightedGraph {
+	gs := append(randomGraphs(t, seed, 30, 200), randomGraphs(t, seed+1, 3, 5000)...)
+	r := rand.New(rand.NewSource(seed + 2))
+	for i := 0; i < 30; i++ {
+		nv := 1 + r.Intn(200)
+		ss, ds, ws := randomEdges(r, nv, r.Intn(nv))
+		g, err := NewWeighted(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dg, err := NewWeightedDiGraph(ss, ds, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs = append(gs, g, dg)
+	}
+	return gs
+}
+
+// forProcs calls fn with each number of goroutines from 1 to maxTestProcs.
+func forProcs(fn func(procs int)) {
+	for procs := 1; procs <= maxTestProcs; procs++ {
+		fn(procs)
+	}
+}
+
+// checkSame fails if the result `got` of the parallel algorithm `name` on g differs from `want`.
+func checkSame(t *testing.T, g Graph, name string, procs int, got, want interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v: %s(procs=%d) = %v, expected %v", g, name, procs, got, want)
+	}
+}
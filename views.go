@@ -0,0 +1,87 @@
+package graph
+
+// reversedGraph is a view of a graph with the direction of every edge reversed.
+type reversedGraph struct {
+	Graph
+}
+
+// reversedWeightedGraph is a view of a weighted graph with the direction of every edge reversed.
+type reversedWeightedGraph struct {
+	reversedGraph
+	wg WeightedGraph
+}
+
+// reverse returns a view of g with the direction of every edge reversed. Undirected graphs are
+// returned as they are. The view keeps the weights of a WeightedGraph.
+func reverse(g Graph) Graph {
+	if !g.IsDirected() {
+		return g
+	}
+	if wg, ok := g.(WeightedGraph); ok {
+		return reversedWeightedGraph{reversedGraph{g}, wg}
+	}
+	return reversedGraph{g}
+}
+
+// reverseWeightFn returns a weight function for the reverse of the graph weighted by weightFn.
+func reverseWeightFn(weightFn func(uint32, uint32) float32) func(uint32, uint32) float32 {
+	if weightFn == nil {
+		return nil
+	}
+	return func(u, v uint32) float32 { return weightFn(v, u) }
+}
+
+func (g reversedGraph) OutDegree(u uint32) uint32      { return g.Graph.InDegree(u) }
+func (g reversedGraph) InDegree(u uint32) uint32       { return g.Graph.OutDegree(u) }
+func (g reversedGraph) OutNeighbors(u uint32) []uint32 { return g.Graph.InNeighbors(u) }
+func (g reversedGraph) InNeighbors(u uint32) []uint32  { return g.Graph.OutNeighbors(u) }
+func (g reversedGraph) HasEdge(u, v uint32) bool       { return g.Graph.HasEdge(v, u) }
+func (g reversedGraph) Edges() EdgeIter                { return newAdjacencyEdgeIter(g) }
+
+func (g reversedWeightedGraph) OutEdgesWeighted(u uint32) ([]uint32, []float32) {
+	return g.wg.InEdgesWeighted(u)
+}
+
+func (g reversedWeightedGraph) InEdgesWeighted(u uint32) ([]uint32, []float32) {
+	return g.wg.OutEdgesWeighted(u)
+}
+
+func (g reversedWeightedGraph) Weight(u, v uint32) (float32, bool) {
+	return g.wg.Weight(v, u)
+}
+
+// adjacencyEdgeIter iterates over the edges of a graph in order of source and destination by
+// walking its out neighbors.
+type adjacencyEdgeIter struct {
+	g         Graph
+	u         uint32
+	neighbors []uint32
+}
+
+func newAdjacencyEdgeIter(g Graph) *adjacencyEdgeIter {
+	it := &adjacencyEdgeIter{g: g}
+	if g.NumVertices() > 0 {
+		it.neighbors = g.OutNeighbors(0)
+		it.skipEmpty()
+	}
+	return it
+}
+
+// skipEmpty moves to the next vertex with out neighbors left to return.
+func (it *adjacencyEdgeIter) skipEmpty() {
+	for len(it.neighbors) == 0 && it.u+1 < it.g.NumVertices() {
+		it.u++
+		it.neighbors = it.g.OutNeighbors(it.u)
+	}
+}
+
+func (it *adjacencyEdgeIter) Next() Edge {
+	e := SimpleEdge{it.u, it.neighbors[0]}
+	it.neighbors = it.neighbors[1:]
+	it.skipEmpty()
+	return e
+}
+
+func (it *adjacencyEdgeIter) Done() bool {
+	return len(it.neighbors) == 0
+}
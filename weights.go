@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"errors"
+	"math"
+
+	"github.com/sbromberger/graphmatrix"
+)
+
+// weigher resolves edge weights for shortest path algorithms. A non-nil weight function
+// takes precedence, then the weights stored in a WeightedGraph, then unit weights.
+type weigher struct {
+	fn func(uint32, uint32) float32
+	wg WeightedGraph
+}
+
+func newWeigher(g Graph, weightFn func(uint32, uint32) float32) weigher {
+	w := weigher{fn: weightFn}
+	if weightFn == nil {
+		if wg, ok := g.(WeightedGraph); ok {
+			w.wg = wg
+		}
+	}
+	return w
+}
+
+// outEdges returns the out neighbors of u and, if weights are stored, the corresponding weights.
+func (w weigher) outEdges(g Graph, u uint32) ([]uint32, []float32) {
+	if w.wg != nil {
+		return w.wg.OutEdgesWeighted(u)
+	}
+	return g.OutNeighbors(u), nil
+}
+
+// inEdges returns the in neighbors of v and, if weights are stored, the corresponding weights.
+func (w weigher) inEdges(g Graph, v uint32) ([]uint32, []float32) {
+	if w.wg != nil {
+		return w.wg.InEdgesWeighted(v)
+	}
+	return g.InNeighbors(v), nil
+}
+
+// weight returns the weight of the edge from u to v, where ws and i are the weights
+// returned by outEdges or inEdges and the index of the edge within them.
+func (w weigher) weight(u, v uint32, ws []float32, i int) float32 {
+	if ws != nil {
+		return ws[i]
+	}
+	if w.fn != nil {
+		return w.fn(u, v)
+	}
+	return 1
+}
+
+// lookup returns the weight of the edge from u to v.
+func (w weigher) lookup(u, v uint32) float32 {
+	if w.wg != nil {
+		wt, _ := w.wg.Weight(u, v)
+		return wt
+	}
+	if w.fn != nil {
+		return w.fn(u, v)
+	}
+	return 1
+}
+
+// sortedWeightedMatrix builds an nv x nv GraphMatrix and its parallel weight vector from
+// vectors of row indices, column indices and weights, which are not modified.
+// If an entry is repeated, the smallest weight is kept.
+func sortedWeightedMatrix(ss, ds []uint32, ws []float32, nv uint32) (graphmatrix.GraphMatrix, []float32, error) {
+	sSs := make([]uint32, len(ss))
+	sDs := make([]uint32, len(ds))
+	copy(sSs, ss)
+	copy(sDs, ds)
+	if err := graphmatrix.SortIJ(&sSs, &sDs); err != nil {
+		return graphmatrix.GraphMatrix{}, nil, err
+	}
+	sSs, sDs = dedupSortedIJ(sSs, sDs)
+	mx := matrixFromSortedIJ(sSs, sDs, nv)
+
+	weights := make([]float32, len(mx.Indices))
+	for i := range weights {
+		weights[i] = float32(math.Inf(1))
+	}
+	for k, u := range ss {
+		i, _ := graphmatrix.SearchSorted32(mx.Indices, ds[k], mx.IndPtr[u], mx.IndPtr[u+1])
+		if ws[k] < weights[i] {
+			weights[i] = ws[k]
+		}
+	}
+	return mx, weights, nil
+}
+
+// checkWeighted returns an error if ss, ds and ws are not the same length.
+func checkWeighted(ss, ds []uint32, ws []float32) error {
+	if len(ss) != len(ds) || len(ss) != len(ws) {
+		return errors.New("source, destination and weight vectors must be the same length")
+	}
+	return nil
+}
+
+// rowWeights returns the weights of row u of mx given its parallel weight vector ws.
+func rowWeights(mx graphmatrix.GraphMatrix, ws []float32, u uint32) []float32 {
+	if u >= mx.Dim() {
+		return []float32{}
+	}
+	return ws[mx.IndPtr[u]:mx.IndPtr[u+1]]
+}
+
+// weightOf returns the weight of entry (u, v) of mx given its parallel weight vector ws.
+func weightOf(mx graphmatrix.GraphMatrix, ws []float32, u, v uint32) (float32, bool) {
+	if u >= mx.Dim() {
+		return 0, false
+	}
+	lo, hi := mx.IndPtr[u], mx.IndPtr[u+1]
+	i, found := graphmatrix.SearchSorted32(mx.Indices, v, lo, hi)
+	if !found {
+		return 0, false
+	}
+	return ws[i], true
+}
+
+// setWeighted sets entry (u, v) of mx and inserts its weight into the parallel weight vector ws.
+func setWeighted(mx *graphmatrix.GraphMatrix, ws *[]float32, u, v uint32, w float32) error {
+	if err := mx.SetIndex(u, v); err != nil {
+		return err
+	}
+	i, _ := graphmatrix.SearchSorted32(mx.Indices, v, mx.IndPtr[u], mx.IndPtr[u+1])
+	*ws = append(*ws, 0)
+	copy((*ws)[i+1:], (*ws)[i:])
+	(*ws)[i] = w
+	return nil
+}